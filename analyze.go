@@ -0,0 +1,181 @@
+package terminfo
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ParamKind is how a parametrized string uses a param.
+type ParamKind byte
+
+const (
+	ParamUnused ParamKind = iota
+	ParamNumber
+	ParamString
+)
+
+func (k ParamKind) String() string {
+	switch k {
+	case ParamUnused:
+		return "unused"
+	case ParamNumber:
+		return "number"
+	case ParamString:
+		return "string"
+	}
+	return fmt.Sprintf("ParamKind(%d)", k)
+}
+
+// Analysis is what Analyze finds out about a parametrized string.
+type Analysis struct {
+	// Params has how each param is used, up to the last one that is,
+	// so that its length is how many args the string takes. As with
+	// TParm, params given to %s or %l are strings, and a string with
+	// no %p takes its args on the stack (as numbers, the first on top).
+	Params []ParamKind
+	// Incr is whether the string has a %i.
+	Incr bool
+	// Variables has the names of the variables it sets or gets,
+	// sorted (so the statics come first).
+	Variables string
+	// MaxStackDepth is the most values it can have on the stack.
+	MaxStackDepth int
+	// Errors has what's wrong with it, by offset: an *ErrBadOp for
+	// what doesn't parse, operations ncurses ignores (ones it doesn't
+	// know, and a % at the end), conditionals that aren't well formed,
+	// and pushing more than the stack holds, and an *ErrStackUnderflow
+	// for popping more than was pushed. Only what doesn't parse makes
+	// Compile fail, and it stops the analysis there too.
+	Errors []error
+}
+
+// Analyze works out what the parametrized string tpl needs, and what's
+// wrong with it, without running it.
+func Analyze(tpl []byte) *Analysis {
+	a := &Analysis{}
+	p, err := compile(tpl, func(pos, end int, err error) {
+		a.Errors = append(a.Errors, &ErrBadOp{Op: string(tpl[pos:end]), Offset: pos, Err: err})
+	})
+
+	var vars [2 * len(variables{})]bool
+	for i := range p.insns {
+		in := &p.insns[i]
+		switch in.op {
+		case opParam:
+			for len(a.Params) < in.arg {
+				a.Params = append(a.Params, ParamUnused)
+			}
+			if p.strParams[in.arg-1] {
+				a.Params[in.arg-1] = ParamString
+			} else {
+				a.Params[in.arg-1] = ParamNumber
+			}
+		case opIncr:
+			a.Incr = true
+		case opSet, opGet:
+			vars[in.arg] = true
+		}
+	}
+	var names []byte
+	for i := len(variables{}); i < len(vars); i++ {
+		if vars[i] {
+			names = append(names, 'A'+byte(i-len(variables{})))
+		}
+	}
+	for i := 0; i < len(variables{}); i++ {
+		if vars[i] {
+			names = append(names, 'a'+byte(i))
+		}
+	}
+	a.Variables = string(names)
+
+	depth := 0
+	if !p.hasParams {
+		depth = p.termcapParams()
+		for i := 0; i < depth; i++ {
+			a.Params = append(a.Params, ParamNumber)
+		}
+	}
+	a.MaxStackDepth = depth
+
+	// the jumps of a program that didn't compile aren't all there
+	if err == nil {
+		a.walk(p, depth)
+	}
+	sort.SliceStable(a.Errors, func(i, j int) bool {
+		return errorOffset(a.Errors[i]) < errorOffset(a.Errors[j])
+	})
+	return a
+}
+
+func errorOffset(err error) int {
+	switch err := err.(type) {
+	case *ErrBadOp:
+		return err.Offset
+	case *ErrStackUnderflow:
+		return err.Offset
+	}
+	return 0
+}
+
+// stackEffect returns how many values an instruction pops, and how many
+// it pushes.
+func stackEffect(op opcode) (pops, pushes int) {
+	switch op {
+	case opText, opIncr, opJump, opIf, opEndIf, opNop:
+		return 0, 0
+	case opPrintf, opSet, opJumpFalse:
+		return 1, 0
+	case opParam, opConst, opGet:
+		return 0, 1
+	case opLen, opNot, opComplement:
+		return 1, 1
+	}
+	return 2, 1
+}
+
+// walk follows every way through the program, starting with depth values
+// on the stack, and keeping track of how deep it gets, for MaxStackDepth
+// and the errors about it. As jumps only go forward, and the stack only
+// gets so deep, that doesn't take long.
+func (a *Analysis) walk(p *Program, depth int) {
+	type state struct{ pc, depth int }
+	seen := make(map[state]bool)
+	bad := make(map[int]bool)
+	var walk func(pc, depth int)
+	walk = func(pc, depth int) {
+		for ; pc < len(p.insns); pc++ {
+			if seen[state{pc, depth}] {
+				return
+			}
+			seen[state{pc, depth}] = true
+			in := &p.insns[pc]
+			pops, pushes := stackEffect(in.op)
+			if depth < pops {
+				if !bad[pc] {
+					bad[pc] = true
+					a.Errors = append(a.Errors, &ErrStackUnderflow{Op: p.op(in), Offset: in.pos})
+				}
+				depth = pops
+			}
+			depth += pushes - pops
+			if depth > stackSize {
+				if !bad[pc] {
+					bad[pc] = true
+					a.Errors = append(a.Errors, &ErrBadOp{Op: p.op(in), Offset: in.pos, Err: ErrStackOverflow})
+				}
+				depth = stackSize
+			}
+			if depth > a.MaxStackDepth {
+				a.MaxStackDepth = depth
+			}
+			switch in.op {
+			case opJumpFalse:
+				walk(in.arg, depth)
+			case opJump:
+				pc = in.arg - 1
+			}
+		}
+	}
+	walk(0, depth)
+}
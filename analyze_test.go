@@ -0,0 +1,163 @@
+package terminfo_test
+
+import (
+	"gopkg.in/check.v1"
+
+	"gopkg.in/terminfo.v0"
+)
+
+func (*tiSuite) TestAnalyze(c *check.C) {
+	const (
+		unused = terminfo.ParamUnused
+		number = terminfo.ParamNumber
+		str    = terminfo.ParamString
+	)
+	for _, s := range []struct {
+		tpl string
+		a   terminfo.Analysis
+	}{
+		{"\x1b[H", terminfo.Analysis{}},
+		{"\x1b[%i%p1%d;%p2%dH", terminfo.Analysis{
+			Params:        []terminfo.ParamKind{number, number},
+			Incr:          true,
+			MaxStackDepth: 1,
+		}},
+		{"\x1b[%?%p1%{8}%<%t3%p1%d%e%p1%{16}%<%t9%p1%{8}%-%d%e38;5;%p1%d%;m", terminfo.Analysis{
+			Params:        []terminfo.ParamKind{number},
+			MaxStackDepth: 2,
+		}},
+		{"%p3%s%p1%l%PA%gA%gb%d", terminfo.Analysis{
+			Params:        []terminfo.ParamKind{str, unused, str},
+			Variables:     "Ab",
+			MaxStackDepth: 2,
+		}},
+		{"%p1%p2%p3%p4%p5%p6%p7%p8%p9%+%+%+%+%+%+%+%+%d", terminfo.Analysis{
+			Params:        []terminfo.ParamKind{number, number, number, number, number, number, number, number, number},
+			MaxStackDepth: 9,
+		}},
+		// args are pushed if there's no %p, as many as TParm pushes
+		{"%d", terminfo.Analysis{
+			Params:        []terminfo.ParamKind{number},
+			MaxStackDepth: 1,
+		}},
+		{"%+%d", terminfo.Analysis{
+			Params:        []terminfo.ParamKind{number, number},
+			MaxStackDepth: 2,
+		}},
+		{"%d%d%d", terminfo.Analysis{
+			Params:        []terminfo.ParamKind{number, number},
+			MaxStackDepth: 2,
+			Errors: []error{
+				&terminfo.ErrStackUnderflow{Op: "%d", Offset: 4},
+			},
+		}},
+		{"\x1b[%i%d;%dR", terminfo.Analysis{
+			Params:        []terminfo.ParamKind{number, number},
+			Incr:          true,
+			MaxStackDepth: 2,
+		}},
+		{"ab%p1%+%d%d", terminfo.Analysis{
+			Params:        []terminfo.ParamKind{number},
+			MaxStackDepth: 1,
+			Errors: []error{
+				&terminfo.ErrStackUnderflow{Op: "%+", Offset: 5},
+				&terminfo.ErrStackUnderflow{Op: "%d", Offset: 9},
+			},
+		}},
+		// only the branch that doesn't push underflows
+		{"%?%p1%t%{1}%;%d", terminfo.Analysis{
+			Params:        []terminfo.ParamKind{number},
+			MaxStackDepth: 1,
+			Errors: []error{
+				&terminfo.ErrStackUnderflow{Op: "%d", Offset: 13},
+			},
+		}},
+		{"%p1%t1%e2%;%;", terminfo.Analysis{
+			Params:        []terminfo.ParamKind{number},
+			MaxStackDepth: 1,
+			Errors: []error{
+				&terminfo.ErrBadOp{Op: "%t", Offset: 3, Err: terminfo.ErrNotInConditional},
+				&terminfo.ErrBadOp{Op: "%e", Offset: 6, Err: terminfo.ErrNotInConditional},
+				&terminfo.ErrBadOp{Op: "%;", Offset: 9, Err: terminfo.ErrNotInConditional},
+				&terminfo.ErrBadOp{Op: "%;", Offset: 11, Err: terminfo.ErrNotInConditional},
+			},
+		}},
+		{"%?%p1%t%?%p2%t1%e2%e3", terminfo.Analysis{
+			Params:        []terminfo.ParamKind{number, number},
+			MaxStackDepth: 1,
+			Errors: []error{
+				&terminfo.ErrBadOp{Op: "%?", Offset: 0, Err: terminfo.ErrUnterminatedConditional},
+				&terminfo.ErrBadOp{Op: "%?", Offset: 7, Err: terminfo.ErrUnterminatedConditional},
+				&terminfo.ErrBadOp{Op: "%e", Offset: 18, Err: terminfo.ErrMissingThen},
+			},
+		}},
+		{"%{1}%{1}%{1}%{1}%{1}%{1}%{1}%{1}%{1}%{1}%{1}%{1}%{1}%{1}%{1}%{1}%{1}%{1}%{1}%{1}%{1}", terminfo.Analysis{
+			MaxStackDepth: 20,
+			Errors: []error{
+				&terminfo.ErrBadOp{Op: "%{1}", Offset: 80, Err: terminfo.ErrStackOverflow},
+			},
+		}},
+		// but not at what ncurses ignores
+		{"%z", terminfo.Analysis{
+			Errors: []error{
+				&terminfo.ErrBadOp{Op: "%z", Offset: 0, Err: terminfo.ErrBadParametrizedString},
+			},
+		}},
+		{"%p1%Q%d", terminfo.Analysis{
+			Params:        []terminfo.ParamKind{number},
+			MaxStackDepth: 1,
+			Errors: []error{
+				&terminfo.ErrBadOp{Op: "%Q", Offset: 3, Err: terminfo.ErrBadParametrizedString},
+			},
+		}},
+		{"abc%", terminfo.Analysis{
+			Errors: []error{
+				&terminfo.ErrBadOp{Op: "%", Offset: 3, Err: terminfo.ErrTruncatedParametrizedString},
+			},
+		}},
+		// it stops at what doesn't parse
+		{"%p1%d%p2%{1x}%d", terminfo.Analysis{
+			Params: []terminfo.ParamKind{number, number},
+			Errors: []error{
+				&terminfo.ErrBadOp{Op: "%{1x}", Offset: 8, Err: terminfo.ErrBadParametrizedString},
+			},
+		}},
+		{"%p1%'ab", terminfo.Analysis{
+			Params: []terminfo.ParamKind{number},
+			Errors: []error{
+				&terminfo.ErrBadOp{Op: "%'ab", Offset: 3, Err: terminfo.ErrBadParametrizedString},
+			},
+		}},
+		{"%p1%2.2", terminfo.Analysis{
+			Params: []terminfo.ParamKind{number},
+			Errors: []error{
+				&terminfo.ErrBadOp{Op: "%2.2", Offset: 3, Err: terminfo.ErrTruncatedParametrizedString},
+			},
+		}},
+	} {
+		c.Check(terminfo.Analyze([]byte(s.tpl)), check.DeepEquals, &s.a, check.Commentf("%q", s.tpl))
+	}
+
+	c.Check(terminfo.ErrBadOp{Op: "%e", Offset: 6, Err: terminfo.ErrNotInConditional}, check.ErrorMatches,
+		`bad parametrized string: %e at offset 6: not in a conditional`)
+	c.Check(terminfo.ParamString.String(), check.Equals, "string")
+}
+
+// real entries are well formed, but for u8, which describes the answer
+// to u9 rather than being sent
+func (*tiSuite) TestAnalyzeXterm(c *check.C) {
+	ti := loadTestdata(c, "xterm-256color")
+	for idx, tpl := range ti.Strings {
+		a := terminfo.Analyze(tpl)
+		if idx == terminfo.User8 {
+			c.Check(a.Errors, check.DeepEquals, []error{
+				&terminfo.ErrBadOp{Op: "%[", Offset: 3, Err: terminfo.ErrBadParametrizedString},
+			})
+			continue
+		}
+		c.Check(a.Errors, check.HasLen, 0, check.Commentf("%v: %q", idx, tpl))
+		c.Check(a.MaxStackDepth <= 20, check.Equals, true)
+	}
+	a := terminfo.Analyze(ti.Strings[terminfo.SetAttributes])
+	c.Check(a.Params, check.HasLen, 9)
+}
@@ -0,0 +1,77 @@
+// Package builtin embeds the compiled descriptions of some common
+// terminals, for when there's no terminfo database to load them from
+// (say, in a minimal container). Importing it registers them as a
+// fallback, looked at after everything in the search path:
+//
+//	import _ "gopkg.in/terminfo.v0/builtin"
+package builtin // import "gopkg.in/terminfo.v0/builtin"
+
+//go:generate ./mkbuiltin.sh
+
+import (
+	"embed"
+	"io/fs"
+	"sync"
+
+	"gopkg.in/terminfo.v0"
+)
+
+// Terminals lists the terminals whose descriptions are embedded. They
+// can also be loaded by their aliases.
+var Terminals = []string{
+	"xterm", "xterm-256color", "screen", "tmux", "linux", "vt100",
+	"rxvt", "alacritty", "kitty", "foot", "dumb",
+}
+
+// db is laid out as a directory-tree terminfo database, without the
+// links for aliases.
+//
+//go:embed terminfo
+var db embed.FS
+
+var (
+	indexOnce sync.Once
+	byName    map[string][]byte
+)
+
+// index maps all the names of the embedded entries to them. As with
+// ncurses, the last name is a description and not an alias, unless it's
+// the only one.
+func index() {
+	byName = make(map[string][]byte)
+	fs.WalkDir(db, "terminfo", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		buf, err := db.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		ti, err := terminfo.Unmarshal(buf)
+		if err != nil {
+			return err
+		}
+		names := ti.Names
+		if len(names) > 1 {
+			names = names[:len(names)-1]
+		}
+		for _, name := range names {
+			if _, ok := byName[name]; !ok {
+				byName[name] = buf
+			}
+		}
+		return nil
+	})
+}
+
+// Lookup returns the compiled description of the named terminal, if
+// it's embedded.
+func Lookup(term string) ([]byte, bool) {
+	indexOnce.Do(index)
+	buf, ok := byName[term]
+	return buf, ok
+}
+
+func init() {
+	terminfo.RegisterFallback(Lookup)
+}
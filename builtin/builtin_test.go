@@ -0,0 +1,65 @@
+package builtin_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"gopkg.in/check.v1"
+
+	"gopkg.in/terminfo.v0"
+	"gopkg.in/terminfo.v0/builtin"
+)
+
+type builtinSuite struct{}
+
+func Test(t *testing.T) { check.TestingT(t) }
+
+var _ = check.Suite(&builtinSuite{})
+
+func (*builtinSuite) TestLookup(c *check.C) {
+	for _, term := range builtin.Terminals {
+		buf, ok := builtin.Lookup(term)
+		c.Assert(ok, check.Equals, true, check.Commentf(term))
+		ti, err := terminfo.Unmarshal(buf)
+		c.Assert(err, check.IsNil, check.Commentf(term))
+		c.Check(ti.Names[0], check.Equals, term)
+	}
+
+	buf, ok := builtin.Lookup("vt100-am")
+	c.Assert(ok, check.Equals, true)
+	ti, err := terminfo.Unmarshal(buf)
+	c.Assert(err, check.IsNil)
+	c.Check(ti.Names[0], check.Equals, "vt100")
+
+	for _, term := range []string{"", "no-such-terminal", ".", "..", "x/../x/xterm"} {
+		_, ok := builtin.Lookup(term)
+		c.Check(ok, check.Equals, false, check.Commentf("%q", term))
+	}
+}
+
+func (*builtinSuite) TestFallback(c *check.C) {
+	dir, err := ioutil.TempDir("", "terminfo")
+	c.Assert(err, check.IsNil)
+	defer os.RemoveAll(dir)
+
+	old := terminfo.CompiledInLocations
+	defer func() { terminfo.CompiledInLocations = old }()
+	terminfo.CompiledInLocations = nil
+	for k, v := range map[string]string{"TERMINFO": dir, "TERMINFO_DIRS": ""} {
+		if old, ok := os.LookupEnv(k); ok {
+			defer os.Setenv(k, old)
+		} else {
+			defer os.Unsetenv(k)
+		}
+		os.Setenv(k, v)
+	}
+
+	ti, err := terminfo.LoadTermF("foot", os.Stdout)
+	c.Assert(err, check.IsNil)
+	c.Check(ti.Names[0], check.Equals, "foot")
+	c.Check(ti.Strings[terminfo.CursorAddress], check.DeepEquals, []byte("\x1b[%i%p1%d;%p2%dH"))
+
+	_, err = terminfo.LoadTermF("no-such-terminal", os.Stdout)
+	c.Check(err, check.NotNil)
+}
@@ -0,0 +1,16 @@
+#!/bin/sh
+# Regenerates the compiled entries under terminfo/ from the system's
+# terminfo database, with ncurses' infocmp and tic. Keep the list in
+# sync with Terminals in builtin.go.
+set -e
+cd "$(dirname "$0")"
+rm -rf terminfo
+mkdir terminfo
+for term in xterm xterm-256color screen tmux linux vt100 rxvt alacritty kitty foot dumb; do
+	infocmp -x "$term" >"terminfo/$term.src"
+	tic -x -o terminfo "terminfo/$term.src"
+	rm "terminfo/$term.src"
+done
+# go:embed skips the symlinks tic makes for aliases; Lookup finds
+# them in the entries' names instead.
+find terminfo -type l -delete
@@ -0,0 +1,68 @@
+// Command tidis prints listings of a terminal's string capabilities, to
+// see what their parametrized strings do:
+//
+//	tidis [-T term] capname...
+//
+// The capnames are terminfo ones, such as setaf, or those of extended
+// capabilities. The terminal defaults to $TERM.
+package main // import "gopkg.in/terminfo.v0/cmd/tidis"
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"gopkg.in/terminfo.v0"
+)
+
+func main() {
+	term := flag.String("T", os.Getenv("TERM"), "the terminal")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [-T term] capname...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	ti, err := terminfo.LoadTerm(*term)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	status := 0
+	for i, name := range flag.Args() {
+		if i > 0 {
+			fmt.Println()
+		}
+		listing, err := disassemble(ti, name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+			status = 1
+			continue
+		}
+		fmt.Printf("%s:\n%s", name, listing)
+	}
+	os.Exit(status)
+}
+
+func disassemble(ti *terminfo.TermInfo, name string) (string, error) {
+	if idx, ok := terminfo.LookupString(name); ok {
+		if _, ok := ti.Strings[idx]; !ok {
+			return "", fmt.Errorf("not set for %s", ti.Names[0])
+		}
+		return ti.Disassemble(idx)
+	}
+	tpl, ok := ti.ExtStrings[name]
+	if !ok {
+		return "", errors.New("no such string capability")
+	}
+	p, err := terminfo.Compile(tpl)
+	if err != nil {
+		return "", err
+	}
+	return p.Disassemble(), nil
+}
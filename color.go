@@ -0,0 +1,394 @@
+package terminfo
+
+import (
+	"fmt"
+	"image/color"
+	"os"
+)
+
+var (
+	Black     = color.RGBA{0, 0, 0, 255}
+	Red       = color.RGBA{205, 0, 0, 255}
+	Green     = color.RGBA{0, 205, 0, 255}
+	Orange    = color.RGBA{205, 205, 0, 255}
+	Blue      = color.RGBA{0, 0, 238, 255}
+	Magenta   = color.RGBA{205, 0, 205, 255}
+	Cyan      = color.RGBA{0, 205, 205, 255}
+	LightGrey = color.RGBA{229, 229, 229, 255}
+
+	DarkGrey     = color.RGBA{127, 127, 127, 255}
+	LightRed     = color.RGBA{255, 0, 0, 255}
+	LightGreen   = color.RGBA{0, 255, 0, 255}
+	Yellow       = color.RGBA{255, 255, 0, 255}
+	LightBlue    = color.RGBA{92, 92, 255, 255}
+	LightMagenta = color.RGBA{255, 0, 255, 255}
+	LightCyan    = color.RGBA{0, 255, 255, 255}
+	White        = color.RGBA{255, 255, 255, 255}
+)
+
+var xterm = color.Palette{
+	// dark colors:
+	Black,
+	Red,
+	Green,
+	Orange,
+	Blue,
+	Magenta,
+	Cyan,
+	LightGrey,
+	// light colors:
+	DarkGrey,
+	LightRed,
+	LightGreen,
+	Yellow,
+	LightBlue,
+	LightMagenta,
+	LightCyan,
+	White,
+}
+
+// xterm88 and xterm256 are the palettes of xterm's 88- and 256-colour
+// modes: its 16 colours, a colour cube, and a greyscale ramp.
+var (
+	xterm88  = xtermPalette([]uint8{0, 139, 205, 255}, []uint8{46, 92, 115, 139, 162, 185, 208, 231})
+	xterm256 = xtermPalette([]uint8{0, 95, 135, 175, 215, 255}, greys256())
+)
+
+func xtermPalette(levels, greys []uint8) color.Palette {
+	p := append(color.Palette(nil), xterm...)
+	for _, r := range levels {
+		for _, g := range levels {
+			for _, b := range levels {
+				p = append(p, color.RGBA{r, g, b, 255})
+			}
+		}
+	}
+	for _, v := range greys {
+		p = append(p, color.RGBA{v, v, v, 255})
+	}
+	return p
+}
+
+func greys256() []uint8 {
+	greys := make([]uint8, 24)
+	for i := range greys {
+		greys[i] = uint8(8 + 10*i)
+	}
+	return greys
+}
+
+// colorIndex returns the index of the colour closest to c that a
+// terminal with n colours has. The first 16 colours of the 88- and
+// 256-colour palettes are left out, as they're often changed from
+// xterm's.
+func colorIndex(c color.Color, n int) int {
+	switch {
+	case n >= 256:
+		return len(xterm) + xterm256[len(xterm):].Index(c)
+	case n >= 88:
+		return len(xterm) + xterm88[len(xterm):].Index(c)
+	case n >= len(xterm):
+		return xterm.Index(c)
+	}
+	return xterm[:n].Index(c)
+}
+
+// TrueColor is what ColorDepth returns for terminals that take colours
+// as 24-bit RGB values.
+const TrueColor = 1 << 24
+
+// ColorDepth returns how many colours the terminal can show: 0, 8, 16,
+// 88, 256 (or however many its MaxColors says), or TrueColor. That's
+// TrueColor if its entry is one of ncurses' direct colour ones, if it
+// has the RGB or Tc extended capabilities, or setrgbf and setrgbb, or
+// if $COLORTERM is "truecolor" or "24bit" and it has colours at all.
+// ti.Colors overrides all that.
+func (ti *TermInfo) ColorDepth() int {
+	switch {
+	case ti.Colors < 0:
+		return 0
+	case ti.Colors > 0:
+		return ti.Colors
+	}
+	n := ti.number(MaxColors)
+	switch {
+	case n <= 0:
+		return 0
+	case n >= TrueColor, ti.hasRGB():
+		return TrueColor
+	}
+	switch os.Getenv("COLORTERM") {
+	case "truecolor", "24bit":
+		return TrueColor
+	}
+	return n
+}
+
+// hasRGB returns whether the terminal's extended capabilities say it
+// takes 24-bit colour. As in ncurses, RGB can be a boolean, a number or
+// a string.
+func (ti *TermInfo) hasRGB() bool {
+	_, rgbNumber := ti.ExtNumbers["RGB"]
+	_, rgbString := ti.ExtStrings["RGB"]
+	return ti.ExtBooleans["RGB"] || rgbNumber || rgbString || ti.ExtBooleans["Tc"] || ti.hasSetRGB()
+}
+
+// hasSetRGB returns whether the terminal has the setrgbf and setrgbb
+// extended capabilities, which set the colours from their red, green
+// and blue.
+func (ti *TermInfo) hasSetRGB() bool {
+	return ti.ExtStrings["setrgbf"] != nil && ti.ExtStrings["setrgbb"] != nil
+}
+
+// Default is the terminal's default colour, as the foreground or the
+// background. nil is too.
+var Default color.Color = defaultColor{}
+
+type defaultColor struct{}
+
+// RGBA returns transparent black, as there's no telling what colour the
+// default is.
+func (defaultColor) RGBA() (r, g, b, a uint32) { return 0, 0, 0, 0 }
+
+func isDefault(c color.Color) bool {
+	return c == nil || c == Default
+}
+
+// Color returns what sets the foreground and background colours to fg
+// and bg, as near as the terminal can get to them, given its
+// ColorDepth: with SetAForeground and SetABackground (or SetForeground
+// and SetBackground, on terminals that only have those), using the
+// closest of the 8, 16, 88 or 256 colours it has, or else in 24-bit
+// colour, with setrgbf and setrgbb if it has them, SetAForeground and
+// SetABackground if its entry is a direct colour one, and ISO-8613-3
+// escapes otherwise. Terminals that can only set colours with
+// InitializePair and SetColorPair get a colour pair for fg and bg; see
+// ColorPair. Either can be Default, as with Foreground and Background.
+func (ti *TermInfo) Color(fg, bg color.Color) string {
+	switch {
+	case ti.ColorDepth() <= 0:
+		return ""
+	case ti.usesPairs():
+		return ti.pairColor(fg, bg, true, true)
+	case isDefault(fg) && isDefault(bg):
+		return ti.ResetColors()
+	case isDefault(fg) && !ti.ExtBooleans["AX"]:
+		return ti.ResetColors() + ti.setColor(bg, bgCaps)
+	case isDefault(bg) && !ti.ExtBooleans["AX"]:
+		return ti.ResetColors() + ti.setColor(fg, fgCaps)
+	case !isDefault(fg) && !isDefault(bg) && ti.isoColor():
+		fc := color.NRGBAModel.Convert(fg).(color.NRGBA)
+		bc := color.NRGBAModel.Convert(bg).(color.NRGBA)
+		return fmt.Sprintf("\033[38;2;%d;%d;%d;48;2;%d;%d;%dm",
+			fc.R, fc.G, fc.B,
+			bc.R, bc.G, bc.B)
+	}
+	return ti.Foreground(fg) + ti.Background(bg)
+}
+
+// Foreground returns what sets the foreground colour to c, as Color
+// does. If c is Default, that's SGR 39 on terminals with the AX extended
+// capability; on others it's ResetColors, which resets the background
+// too. On terminals that use colour pairs, it's a pair for c and the
+// background colour last set.
+func (ti *TermInfo) Foreground(c color.Color) string {
+	switch {
+	case ti.ColorDepth() <= 0:
+		return ""
+	case ti.usesPairs():
+		return ti.pairColor(c, nil, true, false)
+	case !isDefault(c):
+		return ti.setColor(c, fgCaps)
+	case ti.ExtBooleans["AX"]:
+		return "\033[39m"
+	}
+	return ti.ResetColors()
+}
+
+// Background returns what sets the background colour to c, as Color
+// does. If c is Default, that's SGR 49 on terminals with the AX extended
+// capability; on others it's ResetColors, which resets the foreground
+// too. On terminals that use colour pairs, it's a pair for c and the
+// foreground colour last set.
+func (ti *TermInfo) Background(c color.Color) string {
+	switch {
+	case ti.ColorDepth() <= 0:
+		return ""
+	case ti.usesPairs():
+		return ti.pairColor(nil, c, false, true)
+	case !isDefault(c):
+		return ti.setColor(c, bgCaps)
+	case ti.ExtBooleans["AX"]:
+		return "\033[49m"
+	}
+	return ti.ResetColors()
+}
+
+// ResetColors returns what sets the terminal's colours back to its
+// defaults: OrigPair, or else SGR 39 and 49 on terminals with the AX
+// extended capability, or else ExitAttributeMode, which resets all the
+// other attributes as well.
+func (ti *TermInfo) ResetColors() string {
+	if ti.usesPairs() {
+		ti.pairs.Lock()
+		ti.pairs.fg, ti.pairs.bg = nil, nil
+		ti.pairs.Unlock()
+	}
+	return ti.resetColors()
+}
+
+func (ti *TermInfo) resetColors() string {
+	switch {
+	case ti.ColorDepth() <= 0:
+		return ""
+	case ti.Strings[OrigPair] != nil:
+		return ti.MustUnescape(OrigPair)
+	case ti.ExtBooleans["AX"]:
+		return "\033[39;49m"
+	}
+	return ti.MustUnescape(ExitAttributeMode)
+}
+
+// Erase returns what erases with the string capability idx (ClearScreen,
+// ClrEol or EraseChars, say) given args, so that what's erased is in the
+// background colour bg, and whether it will be. Terminals with
+// BackColorErase erase in the current background colour, so that's set
+// to bg first, and left so; others erase in their default one, so
+// unless bg is Default, it's up to the caller to write spaces instead.
+func (ti *TermInfo) Erase(bg color.Color, idx StringIndex, args ...interface{}) (string, bool) {
+	erase := ti.MustUnescape(idx, args...)
+	if !ti.flag(BackColorErase) || ti.ColorDepth() <= 0 {
+		return erase, isDefault(bg)
+	}
+	return ti.Background(bg) + erase, true
+}
+
+// isoColor returns whether the terminal takes 24-bit colours, but only
+// as ISO-8613-3 escapes, as far as we know.
+func (ti *TermInfo) isoColor() bool {
+	return ti.ColorDepth() >= TrueColor && !ti.hasSetRGB() && ti.number(MaxColors) < TrueColor
+}
+
+// colorCaps are what set the foreground or the background colour.
+type colorCaps struct {
+	seta, set StringIndex
+	setrgb    string
+	sgr       int
+}
+
+var (
+	fgCaps = colorCaps{SetAForeground, SetForeground, "setrgbf", 38}
+	bgCaps = colorCaps{SetABackground, SetBackground, "setrgbb", 48}
+)
+
+// setColor returns what sets the foreground or the background colour to
+// c, with caps, as Color says.
+func (ti *TermInfo) setColor(c color.Color, caps colorCaps) string {
+	cols := ti.ColorDepth()
+	switch {
+	case cols < TrueColor && ti.Strings[caps.seta] == nil && ti.Strings[caps.set] != nil:
+		return ti.MustUnescape(caps.set, bgrIndex(colorIndex(c, cols)))
+	case cols < TrueColor:
+		return ti.MustUnescape(caps.seta, colorIndex(c, cols))
+	case ti.hasSetRGB():
+		nc := color.NRGBAModel.Convert(c).(color.NRGBA)
+		return ti.mustUnescapeExt(caps.setrgb, nc.R, nc.G, nc.B)
+	case ti.number(MaxColors) >= TrueColor:
+		// ncurses' direct colour entries take colours as RGB, but
+		// for 0 to 7, which are the palette's (as in xterm-direct):
+		// those are all near enough black for 8 to do instead
+		v := rgb(c)
+		if v < 8 {
+			v = 8
+		}
+		return ti.MustUnescape(caps.seta, v)
+	}
+	nc := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return fmt.Sprintf("\033[%d;2;%d;%d;%dm", caps.sgr, nc.R, nc.G, nc.B)
+}
+
+// bgrIndex returns the number SetForeground and SetBackground have for
+// the colour with the index i in SetAForeground and SetABackground: the
+// first 16 have their red and blue swapped (so that 1 is blue, and 4
+// red), as in ncurses.
+func bgrIndex(i int) int {
+	if i >= 16 {
+		return i
+	}
+	return i&^5 | i&1<<2 | i&4>>2
+}
+
+// mustUnescapeExt evaluates the extended string capability name with the
+// given args, as MustUnescape does the standard ones.
+func (ti *TermInfo) mustUnescapeExt(name string, args ...interface{}) string {
+	eval := Unescape
+	if ti.TParm {
+		eval = TParm
+	}
+	buf, err := eval(ti.ExtStrings[name], args...)
+	if err != nil {
+		panic(err)
+	}
+	return string(buf)
+}
+
+// rgb returns c as a 24-bit RGB value.
+func rgb(c color.Color) int {
+	nc := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return int(nc.R)<<16 | int(nc.G)<<8 | int(nc.B)
+}
+
+// components returns c's red, green and blue from 0 to 1000, or its
+// hue, lightness and saturation on terminals with
+// HueLightnessSaturation, as InitializeColor and InitializePair take
+// them.
+func (ti *TermInfo) components(c color.Color) [3]int {
+	nc := color.NRGBAModel.Convert(c).(color.NRGBA)
+	rgb := [3]int{permille(nc.R), permille(nc.G), permille(nc.B)}
+	if ti.flag(HueLightnessSaturation) {
+		return hls(rgb)
+	}
+	return rgb
+}
+
+// permille scales v from 0 to 1000, rounding up, so that terminals
+// that scale it back rounding down (as xterm does) get v again.
+func permille(v uint8) int {
+	return (int(v)*1000 + 254) / 255
+}
+
+// hls converts red, green and blue from 0 to 1000 to hue (from 0 to
+// 360, with blue at 0 and red at 120, as Tektronix had it), lightness and
+// saturation (from 0 to 100), the way ncurses does.
+func hls(rgb [3]int) [3]int {
+	r, g, b := rgb[0], rgb[1], rgb[2]
+	min, max := r, r
+	for _, v := range rgb[1:] {
+		if v < min {
+			min = v
+		}
+		if v > max {
+			max = v
+		}
+	}
+	l := (min + max) / 20
+	if min == max {
+		return [3]int{0, l, 0}
+	}
+	var s int
+	if l < 50 {
+		s = (max - min) * 100 / (max + min)
+	} else {
+		s = (max - min) * 100 / (2000 - max - min)
+	}
+	var h int
+	switch max {
+	case r:
+		h = (g-b)*60/(max-min) + 120
+	case g:
+		h = (b-r)*60/(max-min) + 240
+	default:
+		h = (r-g)*60/(max-min) + 360
+	}
+	return [3]int{h % 360, l, s}
+}
@@ -0,0 +1,221 @@
+package terminfo_test
+
+import (
+	"image/color"
+	"os"
+
+	"gopkg.in/check.v1"
+
+	"gopkg.in/terminfo.v0"
+)
+
+// colorTerm returns a TermInfo with n colours, whose setaf and setab
+// show the index they're given.
+func colorTerm(n int) *terminfo.TermInfo {
+	ti := &terminfo.TermInfo{
+		Numbers: make([]int16, terminfo.MaxColors+1),
+		Strings: map[terminfo.StringIndex][]byte{
+			terminfo.SetAForeground: []byte("f%p1%d;"),
+			terminfo.SetABackground: []byte("b%p1%d;"),
+		},
+	}
+	ti.Numbers[terminfo.MaxColors] = int16(n)
+	return ti
+}
+
+// setenv sets the environment variable k to v, or unsets it if v is
+// empty, and returns what puts it back.
+func setenv(k, v string) (restore func()) {
+	old, ok := os.LookupEnv(k)
+	if v == "" {
+		os.Unsetenv(k)
+	} else {
+		os.Setenv(k, v)
+	}
+	return func() {
+		if ok {
+			os.Setenv(k, old)
+		} else {
+			os.Unsetenv(k)
+		}
+	}
+}
+
+func (*tiSuite) TestColor(c *check.C) {
+	defer setenv("COLORTERM", "")()
+	grey := color.RGBA{128, 128, 128, 255}
+	orange := color.RGBA{255, 135, 0, 255}
+	for _, s := range []struct {
+		colors int
+		fg, bg color.Color
+		res    string
+	}{
+		{8, terminfo.LightRed, terminfo.Black, "f1;b0;"},
+		{8, terminfo.White, color.Gray{192}, "f7;b7;"},
+		{16, terminfo.White, grey, "f15;b8;"},
+		{16, terminfo.LightRed, terminfo.Blue, "f9;b4;"},
+		// the cube and the greys, but not the first 16
+		{256, terminfo.LightRed, terminfo.Black, "f196;b16;"},
+		{256, grey, orange, "f244;b208;"},
+		{256, color.RGBA{0, 0, 0, 0}, color.Gray{238}, "f16;b255;"},
+		{88, terminfo.LightRed, terminfo.Black, "f64;b16;"},
+		{88, color.Gray{120}, color.RGBA{0, 139, 205, 255}, "f82;b22;"},
+		{0, terminfo.LightRed, terminfo.Black, ""},
+	} {
+		c.Check(colorTerm(s.colors).Color(s.fg, s.bg), check.Equals, s.res, check.Commentf("%d colours", s.colors))
+	}
+
+	ti := loadTestdata(c, "xterm-256color")
+	c.Check(ti.Color(terminfo.LightRed, grey), check.Equals, "\x1b[38;5;196m\x1b[48;5;244m")
+
+	// direct colour, as it says
+	ti = loadTestdata(c, "ext-direct")
+	c.Check(ti.Color(terminfo.LightRed, orange), check.Equals, "\x1b[38;5;16711680m\x1b[48;5;16746240m")
+	// but for what would be the palette's first 8
+	c.Check(ti.Color(terminfo.Black, color.RGBA{0, 0, 7, 255}), check.Equals, "\x1b[38;5;8m\x1b[48;5;8m")
+	c.Check(ti.Color(color.RGBA{0, 0, 8, 255}, color.RGBA{0, 0, 9, 255}), check.Equals, "\x1b[38;5;8m\x1b[48;5;9m")
+	ti = loadTestdata(c, "ext-test")
+	c.Check(ti.Color(terminfo.LightRed, orange), check.Equals, "\x1b[38;2;255;0;0;48;2;255;135;0m")
+}
+
+func (*tiSuite) TestColorDepth(c *check.C) {
+	defer setenv("COLORTERM", "")()
+
+	c.Check(colorTerm(0).ColorDepth(), check.Equals, 0)
+	c.Check(colorTerm(8).ColorDepth(), check.Equals, 8)
+	c.Check(colorTerm(256).ColorDepth(), check.Equals, 256)
+	c.Check(loadTestdata(c, "xterm-256color").ColorDepth(), check.Equals, 256)
+	c.Check(loadTestdata(c, "ext-test").ColorDepth(), check.Equals, terminfo.TrueColor)
+	c.Check(loadTestdata(c, "ext-direct").ColorDepth(), check.Equals, terminfo.TrueColor)
+
+	for _, ext := range []func(ti *terminfo.TermInfo){
+		func(ti *terminfo.TermInfo) { ti.ExtBooleans = map[string]bool{"Tc": true} },
+		func(ti *terminfo.TermInfo) { ti.ExtBooleans = map[string]bool{"RGB": true} },
+		func(ti *terminfo.TermInfo) { ti.ExtNumbers = map[string]int32{"RGB": 8} },
+		func(ti *terminfo.TermInfo) { ti.ExtStrings = map[string][]byte{"RGB": []byte("8/8/8")} },
+		func(ti *terminfo.TermInfo) {
+			ti.ExtStrings = map[string][]byte{"setrgbf": []byte("x"), "setrgbb": []byte("y")}
+		},
+	} {
+		ti := colorTerm(256)
+		ext(ti)
+		c.Check(ti.ColorDepth(), check.Equals, terminfo.TrueColor)
+	}
+
+	restore := setenv("COLORTERM", "truecolor")
+	c.Check(colorTerm(8).ColorDepth(), check.Equals, terminfo.TrueColor)
+	c.Check(colorTerm(0).ColorDepth(), check.Equals, 0)
+	setenv("COLORTERM", "24bit")
+	c.Check(colorTerm(256).ColorDepth(), check.Equals, terminfo.TrueColor)
+	setenv("COLORTERM", "yes")
+	c.Check(colorTerm(256).ColorDepth(), check.Equals, 256)
+	restore()
+
+	ti := loadTestdata(c, "ext-direct")
+	ti.Colors = 16
+	c.Check(ti.ColorDepth(), check.Equals, 16)
+	c.Check(ti.Color(terminfo.LightRed, terminfo.Blue), check.Equals, "\x1b[38;5;9m\x1b[48;5;4m")
+	ti.Colors = -1
+	c.Check(ti.ColorDepth(), check.Equals, 0)
+	c.Check(ti.Color(terminfo.LightRed, terminfo.Blue), check.Equals, "")
+	ti = colorTerm(0)
+	ti.Colors = 256
+	c.Check(ti.Color(terminfo.LightRed, terminfo.Blue), check.Equals, "f196;b21;")
+}
+
+func (*tiSuite) TestColorSetRGB(c *check.C) {
+	for _, term := range []string{"ext-test", "ext-direct"} {
+		ti := loadTestdata(c, term)
+		ti.ExtStrings["setrgbf"] = []byte("\x1b[38:2::%p1%d:%p2%d:%p3%dm")
+		ti.ExtStrings["setrgbb"] = []byte("\x1b[48:2::%p1%d:%p2%d:%p3%dm")
+		c.Check(ti.Color(terminfo.Yellow, color.Gray16{0x8080}), check.Equals, "\x1b[38:2::255:255:0m\x1b[48:2::128:128:128m", check.Commentf(term))
+	}
+}
+
+func (*tiSuite) TestDefaultColors(c *check.C) {
+	defer setenv("COLORTERM", "")()
+
+	// xterm has AX, so can set each to the default on its own
+	ti := loadTestdata(c, "xterm-256color")
+	for _, def := range []color.Color{nil, terminfo.Default} {
+		c.Check(ti.Foreground(def), check.Equals, "\x1b[39m")
+		c.Check(ti.Background(def), check.Equals, "\x1b[49m")
+		c.Check(ti.Color(terminfo.LightRed, def), check.Equals, "\x1b[38;5;196m\x1b[49m")
+		c.Check(ti.Color(def, terminfo.LightRed), check.Equals, "\x1b[39m\x1b[48;5;196m")
+		c.Check(ti.Color(def, def), check.Equals, "\x1b[39;49m")
+	}
+	c.Check(ti.Foreground(terminfo.LightRed), check.Equals, "\x1b[38;5;196m")
+	c.Check(ti.Background(terminfo.LightRed), check.Equals, "\x1b[48;5;196m")
+	c.Check(ti.ResetColors(), check.Equals, "\x1b[39;49m")
+
+	// others reset both with op, or sgr0 if that's all they have
+	ti = colorTerm(8)
+	ti.Strings[terminfo.OrigPair] = []byte("op;")
+	ti.Strings[terminfo.ExitAttributeMode] = []byte("sgr0;")
+	c.Check(ti.ResetColors(), check.Equals, "op;")
+	c.Check(ti.Foreground(nil), check.Equals, "op;")
+	c.Check(ti.Color(nil, terminfo.Red), check.Equals, "op;b1;")
+	c.Check(ti.Color(terminfo.Red, nil), check.Equals, "op;f1;")
+	delete(ti.Strings, terminfo.OrigPair)
+	c.Check(ti.ResetColors(), check.Equals, "sgr0;")
+	c.Check(ti.Background(nil), check.Equals, "sgr0;")
+	ti.ExtBooleans = map[string]bool{"AX": true}
+	c.Check(ti.ResetColors(), check.Equals, "\x1b[39;49m")
+
+	// no colours, nothing to do
+	ti = colorTerm(0)
+	ti.Strings[terminfo.OrigPair] = []byte("op;")
+	c.Check(ti.ResetColors(), check.Equals, "")
+	c.Check(ti.Foreground(terminfo.Red), check.Equals, "")
+	c.Check(ti.Background(nil), check.Equals, "")
+
+	// 24-bit colour, with and without AX
+	ti = loadTestdata(c, "ext-test")
+	ti.Strings[terminfo.OrigPair] = []byte("op;")
+	c.Check(ti.Foreground(terminfo.Yellow), check.Equals, "\x1b[38;2;255;255;0m")
+	c.Check(ti.Background(terminfo.Yellow), check.Equals, "\x1b[48;2;255;255;0m")
+	c.Check(ti.Color(terminfo.Yellow, nil), check.Equals, "op;\x1b[38;2;255;255;0m")
+	ti.ExtBooleans["AX"] = true
+	c.Check(ti.Color(terminfo.Yellow, nil), check.Equals, "\x1b[38;2;255;255;0m\x1b[49m")
+}
+
+func (*tiSuite) TestErase(c *check.C) {
+	defer setenv("COLORTERM", "")()
+
+	// xterm has bce, so erases in whatever the background is
+	ti := loadTestdata(c, "xterm-256color")
+	s, ok := ti.Erase(terminfo.Blue, terminfo.ClrEol)
+	c.Check(s, check.Equals, "\x1b[48;5;21m\x1b[K")
+	c.Check(ok, check.Equals, true)
+	s, ok = ti.Erase(nil, terminfo.EraseChars, 5)
+	c.Check(s, check.Equals, "\x1b[49m\x1b[5X")
+	c.Check(ok, check.Equals, true)
+
+	// without it, only in the default colour
+	ti.Booleans[terminfo.BackColorErase] = false
+	s, ok = ti.Erase(terminfo.Default, terminfo.ClrEol)
+	c.Check(s, check.Equals, "\x1b[K")
+	c.Check(ok, check.Equals, true)
+	s, ok = ti.Erase(terminfo.Blue, terminfo.ClrEol)
+	c.Check(s, check.Equals, "\x1b[K")
+	c.Check(ok, check.Equals, false)
+}
+
+func (*tiSuite) TestColorSetF(c *check.C) {
+	defer setenv("COLORTERM", "")()
+
+	// setf and setb number the colours BGR
+	ti := colorTerm(16)
+	ti.Strings = map[terminfo.StringIndex][]byte{
+		terminfo.SetForeground: []byte("f%p1%d;"),
+		terminfo.SetBackground: []byte("b%p1%d;"),
+	}
+	c.Check(ti.Color(terminfo.Red, terminfo.Black), check.Equals, "f4;b0;")
+	c.Check(ti.Color(terminfo.Blue, terminfo.Green), check.Equals, "f1;b2;")
+	c.Check(ti.Color(terminfo.Yellow, terminfo.LightCyan), check.Equals, "f14;b11;")
+	c.Check(ti.Foreground(terminfo.LightMagenta), check.Equals, "f13;")
+
+	// but setaf and setab come first
+	ti.Strings[terminfo.SetAForeground] = []byte("af%p1%d;")
+	c.Check(ti.Color(terminfo.Red, terminfo.Black), check.Equals, "af1;b0;")
+}
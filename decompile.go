@@ -0,0 +1,229 @@
+package terminfo
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Layout of decompiled entries, as infocmp's defaults.
+const (
+	sourceWidth  = 60
+	sourceIndent = 8
+)
+
+// Source returns the TermInfo as terminfo source. See WriteSource.
+func (ti *TermInfo) Source() string {
+	var buf bytes.Buffer
+	ti.WriteSource(&buf)
+	return buf.String()
+}
+
+// WriteSource writes the TermInfo to w as terminfo source: its names,
+// and then its booleans, numbers and strings, each sorted by capname,
+// extended capabilities last. Lines are wrapped and strings escaped the
+// way infocmp -x does, so that the output of both can be diffed.
+//
+// One difference is that cancelled capabilities aren't kept when an
+// entry is read, compiled or from source, so where infocmp -x writes
+// xx@ for those an entry cancels, WriteSource leaves them out, as if
+// they were absent.
+func (ti *TermInfo) WriteSource(w io.Writer) error {
+	return ti.writeSource(w, false)
+}
+
+// Termcap returns the TermInfo in termcap format. See WriteTermcap.
+func (ti *TermInfo) Termcap() string {
+	var buf bytes.Buffer
+	ti.WriteTermcap(&buf)
+	return buf.String()
+}
+
+// WriteTermcap writes the TermInfo to w in termcap format, as infocmp -Cr
+// does, sorted by termcap name. Capabilities that have no termcap name,
+// which include all the extended ones, are left out. Unlike infocmp,
+// parameterized strings are written as they are instead of being
+// translated.
+func (ti *TermInfo) WriteTermcap(w io.Writer) error {
+	return ti.writeSource(w, true)
+}
+
+// sourceItem is a capability as written in source, with its name to
+// sort by.
+type sourceItem struct {
+	name, text string
+}
+
+func sortItems(items []sourceItem) {
+	sort.Slice(items, func(i, j int) bool { return items[i].name < items[j].name })
+}
+
+func (ti *TermInfo) writeSource(w io.Writer, termcap bool) error {
+	boolNames, numNames, strNames := booleanCapNames[:], numberCapNames[:], stringCapNames[:]
+	if termcap {
+		boolNames, numNames, strNames = booleanTermcapNames[:], numberTermcapNames[:], stringTermcapNames[:]
+	}
+
+	var bools, nums, strs []sourceItem
+	for i, b := range ti.Booleans {
+		if b && i < len(boolNames) && boolNames[i] != "" {
+			bools = append(bools, sourceItem{boolNames[i], boolNames[i]})
+		}
+	}
+	for i, n := range ti.numbers() {
+		if n >= 0 && i < len(numNames) && numNames[i] != "" {
+			nums = append(nums, sourceItem{numNames[i], numNames[i] + "#" + formatNumber(n, termcap)})
+		}
+	}
+	for i, str := range ti.Strings {
+		if int(i) < len(strNames) && strNames[i] != "" {
+			strs = append(strs, sourceItem{strNames[i], strNames[i] + "=" + escapeSource(str, termcap)})
+		}
+	}
+	sortItems(bools)
+	sortItems(nums)
+	sortItems(strs)
+
+	if !termcap {
+		for _, name := range sortedKeys(ti.ExtBooleans) {
+			if ti.ExtBooleans[name] {
+				bools = append(bools, sourceItem{name, name})
+			}
+		}
+		for _, name := range sortedKeys(ti.ExtNumbers) {
+			if n := ti.ExtNumbers[name]; n >= 0 {
+				nums = append(nums, sourceItem{name, name + "#" + formatNumber(n, termcap)})
+			}
+		}
+		for _, name := range sortedKeys(ti.ExtStrings) {
+			strs = append(strs, sourceItem{name, name + "=" + escapeSource(ti.ExtStrings[name], termcap)})
+		}
+	}
+
+	sw := &sourceWriter{sep: " ", end: ",", wrap: "\n\t"}
+	if termcap {
+		sw = &sourceWriter{sep: "", end: ":", wrap: "\\\n\t:"}
+	}
+	sw.buf.WriteString(strings.Join(ti.Names, "|"))
+	sw.buf.WriteString(sw.end)
+	for _, items := range [][]sourceItem{bools, nums, strs} {
+		sw.line(items)
+	}
+	sw.buf.WriteByte('\n')
+
+	_, err := sw.buf.WriteTo(w)
+	return err
+}
+
+// sourceWriter lays out capabilities in lines of up to sourceWidth
+// columns.
+type sourceWriter struct {
+	buf bytes.Buffer
+	// what goes between two capabilities on the same line, after each
+	// capability, and at the end of a line
+	sep, end, wrap string
+	col            int
+}
+
+// line starts a new line and writes items to it, wrapping as needed.
+func (sw *sourceWriter) line(items []sourceItem) {
+	if len(items) == 0 {
+		return
+	}
+	sw.newline()
+	for i, item := range items {
+		gap := len(sw.sep) + len(sw.end)
+		if sw.col > sourceIndent && sw.col+gap+len(item.text) > sourceWidth {
+			sw.newline()
+		} else if i > 0 {
+			sw.buf.WriteString(sw.sep)
+		}
+		sw.buf.WriteString(item.text)
+		sw.buf.WriteString(sw.end)
+		// as infocmp's, the column doesn't account for the separators
+		sw.col += len(item.text)
+	}
+}
+
+func (sw *sourceWriter) newline() {
+	sw.buf.WriteString(sw.wrap)
+	sw.col = sourceIndent
+}
+
+// formatNumber formats a number as infocmp does: in hex if it's close to
+// a power of two, except in termcap.
+func formatNumber(n int32, termcap bool) string {
+	if !termcap && n > 255 {
+		for bit := uint(8); bit < 32; bit++ {
+			if m := int64(1) << bit; m-16 <= int64(n) && int64(n) < m+16 {
+				return fmt.Sprintf("%#x", n)
+			}
+		}
+	}
+	return fmt.Sprint(n)
+}
+
+// escapeSource escapes a string capability's value, as infocmp does;
+// unescapeSource reverses it.
+func escapeSource(s []byte, termcap bool) string {
+	// control characters are written as ^X, unless what's left without
+	// them is more than 3 characters long, which calls for octal
+	str, rest := escapeSource1(s, termcap, false)
+	if rest > 3 {
+		str, _ = escapeSource1(s, termcap, true)
+	}
+	return str
+}
+
+// escapeSource1 escapes s, with control characters in octal if long is
+// true, and returns it along with its length not counting the control
+// characters written as ^X.
+func escapeSource1(s []byte, termcap, long bool) (string, int) {
+	var buf strings.Builder
+	ctls := 0
+	isPrint := func(c byte) bool { return c >= ' ' && c < 0177 }
+	isSpecial := func(c byte) bool { return c == '\\' || c == ',' && !termcap || c == ':' && termcap }
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '%' && i+1 < len(s) && isPrint(s[i+1]) && !isSpecial(s[i+1]):
+			// parameters, such as %^, are left alone
+			buf.WriteByte(c)
+			i++
+			buf.WriteByte(s[i])
+		case c == 0200:
+			buf.WriteString(`\0`)
+		case c == 033:
+			buf.WriteString(`\E`)
+		case c == '\\':
+			buf.WriteString(`\\`)
+		case c == ' ' && (i == 0 || len(bytes.Trim(s[i:], " ")) == 0):
+			// leading and trailing spaces would get lost
+			buf.WriteString(`\s`)
+		case c == ',' && !termcap, c == '^' && !termcap:
+			buf.WriteByte('\\')
+			buf.WriteByte(c)
+		case c == ':' && termcap, c == '^' && termcap:
+			fmt.Fprintf(&buf, `\%03o`, c)
+		case isPrint(c):
+			buf.WriteByte(c)
+		case c == '\r':
+			buf.WriteString(`\r`)
+		case c == '\n':
+			buf.WriteString(`\n`)
+		case c < ' ' && i+1 < len(s) && s[i+1] >= '0' && s[i+1] <= '9':
+			// an octal escape would swallow the digit
+			buf.WriteByte('^')
+			buf.WriteByte(c + '@')
+		case c < ' ' && !long, c == 0177 && !long && !termcap:
+			buf.WriteByte('^')
+			buf.WriteByte((c + '@') & 0177)
+			ctls++
+		default:
+			fmt.Fprintf(&buf, `\%03o`, c)
+		}
+	}
+	return buf.String(), buf.Len() - 2*ctls
+}
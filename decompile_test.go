@@ -0,0 +1,102 @@
+package terminfo_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+
+	"gopkg.in/check.v1"
+
+	"gopkg.in/terminfo.v0"
+)
+
+func (*tiSuite) TestSourceLikeInfocmp(c *check.C) {
+	buf, err := ioutil.ReadFile("testdata/xterm-256color.src")
+	c.Assert(err, check.IsNil)
+	var src []string
+	for _, line := range strings.SplitAfter(string(buf), "\n") {
+		if !strings.HasPrefix(line, "#") {
+			src = append(src, line)
+		}
+	}
+
+	ti := loadTestdata(c, "xterm-256color")
+	c.Check(ti.Source(), check.Equals, strings.Join(src, ""))
+	var w bytes.Buffer
+	c.Assert(ti.WriteSource(&w), check.IsNil)
+	c.Check(w.String(), check.Equals, strings.Join(src, ""))
+}
+
+func (*tiSuite) TestSourceRoundTrip(c *check.C) {
+	for _, term := range []string{"ext-test", "ext-direct", "xterm-256color"} {
+		ti := loadTestdata(c, term)
+		tis := parseSource(c, ti.Source())
+		c.Assert(tis, check.HasLen, 1)
+		c.Check(tis[0].Source(), check.Equals, ti.Source())
+
+		buf, err := ti.Marshal()
+		c.Assert(err, check.IsNil)
+		out, err := tis[0].Marshal()
+		c.Assert(err, check.IsNil)
+		c.Check(out, check.DeepEquals, buf, check.Commentf(term))
+	}
+}
+
+func (*tiSuite) TestSourceEscapes(c *check.C) {
+	ti := &terminfo.TermInfo{
+		Names:   []string{"esc", "escapes"},
+		Numbers: []int16{80, -1, 300},
+		Strings: map[terminfo.StringIndex][]byte{
+			terminfo.User0: []byte("\x1b[%p1%p2%^%dm"),
+			terminfo.User1: []byte(" a, b "),
+			terminfo.User2: []byte("\x01\x02"),
+			terminfo.User3: []byte("abcd\x01"),
+			terminfo.User4: []byte("\x7f"),
+			terminfo.User5: []byte("ab\x010cd"),
+			terminfo.User6: []byte("\x80\\^"),
+		},
+	}
+	// as infocmp -x and infocmp -Cr, except that infocmp -Cr also
+	// comments out u0 (..u0=), as %^ can't be translated to termcap
+	c.Check(ti.Source(), check.Equals, `esc|escapes,
+	cols#80, lines#300,
+	u0=\E[%p1%p2%^%dm, u1=\sa\, b\s, u2=^A^B, u3=abcd\001,
+	u4=^?, u5=ab^A0cd, u6=\0\\\^,
+`)
+	c.Check(ti.Termcap(), check.Equals, `esc|escapes:\
+	:co#80:li#300:\
+	:u0=\E[%p1%p2%^%dm:u1=\sa, b\s:u2=^A^B:u3=abcd\001:\
+	:u4=\177:u5=ab^A0cd:u6=\0\\\136:
+`)
+
+	tis := parseSource(c, ti.Source())
+	c.Assert(tis, check.HasLen, 1)
+	c.Check(tis[0].Strings, check.DeepEquals, ti.Strings)
+}
+
+func (*tiSuite) TestSourceRoundTripControlBackslash(c *check.C) {
+	ti := &terminfo.TermInfo{
+		Names: []string{"ctlbs", "t"},
+		Strings: map[terminfo.StringIndex][]byte{
+			terminfo.CursorRight:   []byte("\x1c"),
+			terminfo.CursorAddress: []byte("A"),
+			terminfo.User0:         []byte("a\x1cb"),
+		},
+	}
+	c.Check(ti.Source(), check.Equals, `ctlbs|t,
+	cuf1=^\, cup=A, u0=a^\b,
+`)
+	tis := parseSource(c, ti.Source())
+	c.Assert(tis, check.HasLen, 1)
+	c.Check(tis[0].Strings, check.DeepEquals, ti.Strings)
+}
+
+func (*tiSuite) TestTermcap(c *check.C) {
+	ti := loadTestdata(c, "ext-test")
+	c.Check(ti.Termcap(), check.Equals, `ext-test|ext|a terminal with some extended capabilities:\
+	:am:ut:\
+	:Co#256:co#80:li#24:pa#64:\
+	:AB=\E[48;5;%p1%dm:AF=\E[38;5;%p1%dm:bl=^G:cl=\E[H\E[2J:\
+	:cm=\E[%i%p1%d;%p2%dH:
+`)
+}
@@ -0,0 +1,199 @@
+package terminfo
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Layout of listings.
+const (
+	listingIndent = 4
+	listingColumn = 24
+)
+
+// Disassemble returns a listing of the string capability idx. See
+// Program.WriteListing.
+func (ti *TermInfo) Disassemble(idx StringIndex) (string, error) {
+	p, err := ti.Program(idx)
+	if err != nil {
+		return "", err
+	}
+	return p.Disassemble(), nil
+}
+
+// Disassemble returns a listing of the program. See WriteListing.
+func (p *Program) Disassemble() string {
+	var buf bytes.Buffer
+	p.WriteListing(&buf)
+	return buf.String()
+}
+
+// WriteListing writes a listing of the program to w: each operation on a
+// line of its own, as it is in the string, with what it does next to it.
+// Literal text is escaped as in terminfo source, with its padding on
+// separate lines, what's between %? and %; is indented, and operations
+// that ncurses ignores, as it doesn't know them, are listed as such. For
+// example:
+//
+//	\E[                     text
+//	%?                      if
+//	    %p1                 push param 1
+//	    %{8}                push 8
+//	    %<                  less than
+//	%t                      then
+//	    3                   text
+//	    %p1                 push param 1
+//	    %d                  print in decimal
+//	%e                      else
+//	...
+func (p *Program) WriteListing(w io.Writer) error {
+	var buf bytes.Buffer
+	line := func(level int, src, what string) {
+		buf.WriteString(strings.Repeat(" ", level*listingIndent))
+		buf.WriteString(src)
+		n := listingColumn - level*listingIndent - len(src)
+		if n < 2 {
+			n = 2
+		}
+		buf.WriteString(strings.Repeat(" ", n))
+		buf.WriteString(what)
+		buf.WriteByte('\n')
+	}
+	level := 0
+	outer := func() int {
+		if level > 0 {
+			return level - 1
+		}
+		return 0
+	}
+	for i := range p.insns {
+		in := &p.insns[i]
+		src := string(p.src[in.pos:in.end])
+		switch in.op {
+		case opText:
+			text := p.src[in.pos:in.end]
+			o := 0
+			for _, idx := range findPadIndexes(text, -1) {
+				if idx[0] > o {
+					line(level, escapeSource(text[o:idx[0]], false), "text")
+				}
+				line(level, string(text[idx[0]:idx[1]]), describePad(text, idx))
+				o = idx[1]
+			}
+			if o < len(text) {
+				line(level, escapeSource(text[o:], false), "text")
+			}
+		case opIf:
+			line(level, src, "if")
+			level++
+		case opJumpFalse:
+			line(outer(), src, "then")
+		case opJump:
+			line(outer(), src, "else")
+		case opEndIf:
+			level = outer()
+			line(level, src, "end if")
+		default:
+			line(level, src, p.describe(in))
+		}
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// describePad describes the padding text[idx[0]:idx[1]], as found by
+// findPadIndexes.
+func describePad(text []byte, idx []int) string {
+	what := fmt.Sprintf("pad %sms", text[idx[2]:idx[3]])
+	if idx[4] >= 0 {
+		what += " per line affected"
+	}
+	if idx[6] >= 0 {
+		what += ", mandatory"
+	}
+	return what
+}
+
+var opDescriptions = [...]string{
+	opLen:        "push the length of a string",
+	opAdd:        "add",
+	opSub:        "subtract",
+	opMul:        "multiply",
+	opDiv:        "divide",
+	opMod:        "modulo",
+	opAnd:        "bitwise and",
+	opOr:         "bitwise or",
+	opXor:        "bitwise xor",
+	opEq:         "equal",
+	opLt:         "less than",
+	opGt:         "greater than",
+	opLogicalAnd: "logical and",
+	opLogicalOr:  "logical or",
+	opNot:        "logical not",
+	opComplement: "bitwise complement",
+	opIncr:       "add 1 to params 1 and 2",
+	opNop:        "ignored",
+}
+
+// describe says what an instruction does, for listings.
+func (p *Program) describe(in *insn) string {
+	switch in.op {
+	case opPrintf:
+		return "print " + in.f.describe(byte(in.arg))
+	case opParam:
+		return fmt.Sprintf("push param %d", in.arg)
+	case opConst:
+		return fmt.Sprintf("push %d", in.arg)
+	case opSet, opGet:
+		name := "variable " + string('a'+byte(in.arg))
+		if in.arg >= len(variables{}) {
+			name = "static variable " + string('A'+byte(in.arg-len(variables{})))
+		}
+		if in.op == opSet {
+			return "pop into " + name
+		}
+		return "push " + name
+	}
+	return opDescriptions[in.op]
+}
+
+// describe says how a format prints, for listings.
+func (f format) describe(verb byte) string {
+	var what []string
+	switch verb {
+	case 'd':
+		what = append(what, "in decimal")
+	case 'o':
+		what = append(what, "in octal")
+	case 'x':
+		what = append(what, "in hex")
+	case 'X':
+		what = append(what, "in upper-case hex")
+	case 's':
+		what = append(what, "a string")
+	case 'c':
+		// the rest of the format is ignored
+		return "a character"
+	}
+	if f.flags&flagMinus != 0 {
+		what = append(what, "left-justified")
+	}
+	if f.flags&flagSpace != 0 {
+		what = append(what, "with a space if positive")
+	}
+	if f.flags&flagAlt != 0 {
+		what = append(what, "in alternate form")
+	}
+	if f.width > 0 {
+		what = append(what, fmt.Sprintf("width %d", f.width))
+	}
+	if f.flags&flagZero != 0 {
+		what = append(what, "zero-padded")
+	}
+	if f.prec >= 0 {
+		what = append(what, fmt.Sprintf("precision %d", f.prec))
+	}
+	return strings.Join(what, ", ")
+}
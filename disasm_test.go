@@ -0,0 +1,88 @@
+package terminfo_test
+
+import (
+	"gopkg.in/check.v1"
+
+	"gopkg.in/terminfo.v0"
+)
+
+func (*tiSuite) TestDisassemble(c *check.C) {
+	p, err := terminfo.Compile([]byte("\x1b[%?%p1%{8}%<%t3%p1%d%e%p1%{16}%<%t9%p1%{8}%-%d%e38;5;%p1%d%;m"))
+	c.Assert(err, check.IsNil)
+	c.Check(p.Disassemble(), check.Equals, `\E[                     text
+%?                      if
+    %p1                 push param 1
+    %{8}                push 8
+    %<                  less than
+%t                      then
+    3                   text
+    %p1                 push param 1
+    %d                  print in decimal
+%e                      else
+    %p1                 push param 1
+    %{16}               push 16
+    %<                  less than
+%t                      then
+    9                   text
+    %p1                 push param 1
+    %{8}                push 8
+    %-                  subtract
+    %d                  print in decimal
+%e                      else
+    38;5;               text
+    %p1                 push param 1
+    %d                  print in decimal
+%;                      end if
+m                       text
+`)
+
+	p, err = terminfo.Compile([]byte("%?%p1%t%?%p2%PA%gA%t %e\x0e%;%;%p3%:-5.2x%%$<5*/>%p4%l%'a'%c"))
+	c.Assert(err, check.IsNil)
+	c.Check(p.Disassemble(), check.Equals, `%?                      if
+    %p1                 push param 1
+%t                      then
+    %?                  if
+        %p2             push param 2
+        %PA             pop into static variable A
+        %gA             push static variable A
+    %t                  then
+        \s              text
+    %e                  else
+        ^N              text
+    %;                  end if
+%;                      end if
+%p3                     push param 3
+%:-5.2x                 print in hex, left-justified, width 5, precision 2
+%%                      text
+$<5*/>                  pad 5ms per line affected, mandatory
+%p4                     push param 4
+%l                      push the length of a string
+%'a'                    push 97
+%c                      print a character
+`)
+
+	// ncurses ignores what it doesn't know
+	p, err = terminfo.Compile([]byte("a%zb%p1%Q%d%"))
+	c.Assert(err, check.IsNil)
+	c.Check(p.Disassemble(), check.Equals, `a                       text
+%z                      ignored
+b                       text
+%p1                     push param 1
+%Q                      ignored
+%d                      print in decimal
+%                       ignored
+`)
+
+	ti := &terminfo.TermInfo{Strings: map[terminfo.StringIndex][]byte{
+		terminfo.FlashScreen: []byte("\x1b[?5h$<100/>\x1b[?5l"),
+		terminfo.User0:       []byte("%{1"),
+	}}
+	listing, err := ti.Disassemble(terminfo.FlashScreen)
+	c.Assert(err, check.IsNil)
+	c.Check(listing, check.Equals, `\E[?5h                  text
+$<100/>                 pad 100ms, mandatory
+\E[?5l                  text
+`)
+	_, err = ti.Disassemble(terminfo.User0)
+	c.Check(err, check.Equals, terminfo.ErrTruncatedParametrizedString)
+}
@@ -11,6 +11,17 @@ var (
 	ErrTruncatedParametrizedString = errors.New("truncated parametrized string")
 	ErrBadParametrizedString       = errors.New("bad parametrized string")
 	ErrMissingArgs                 = errors.New("missing args")
+
+	// what Analyze finds wrong with conditionals
+	ErrNotInConditional        = errors.New("not in a conditional")
+	ErrMissingThen             = errors.New("no %t before it")
+	ErrUnterminatedConditional = errors.New("no %; to end it")
+	ErrStackOverflow           = errors.New("too many values on the stack")
+
+	// what Palette returns for what the terminal can't do
+	ErrCantChangeColors  = errors.New("terminal can't change its colours")
+	ErrNoSuchColor       = errors.New("no such colour")
+	ErrCantRestoreColors = errors.New("terminal can't put its colours back")
 )
 
 type ErrBadThing struct {
@@ -20,5 +31,58 @@ type ErrBadThing struct {
 }
 
 func (e ErrBadThing) Error() string {
+	if e.Filename == "" {
+		return fmt.Sprintf("bad %s in terminfo entry: %v", e.Thing, e.Err)
+	}
 	return fmt.Sprintf("bad %s in terminfo file %q: %v", e.Thing, e.Filename, e.Err)
 }
+
+// ErrBadSource is returned by ParseSource for malformed terminfo source.
+type ErrBadSource struct {
+	Line int
+	Err  error
+}
+
+func (e ErrBadSource) Error() string {
+	return fmt.Sprintf("bad terminfo source at line %d: %v", e.Line, e.Err)
+}
+
+// ErrStackUnderflow is returned when evaluating a parametrized string
+// pops a value off an empty stack.
+type ErrStackUnderflow struct {
+	// the operation, e.g. "%+", and where it is in the string
+	Op     string
+	Offset int
+}
+
+func (e ErrStackUnderflow) Error() string {
+	return fmt.Sprintf("stack underflow in parametrized string: %s at offset %d", e.Op, e.Offset)
+}
+
+// ErrTypeMismatch is returned when evaluating a parametrized string
+// finds a string where it needs a number, or the other way around, or is
+// given an argument of a type it doesn't handle.
+type ErrTypeMismatch struct {
+	// the operation, e.g. "%d", and where it is in the string
+	Op     string
+	Offset int
+	// "number" or "string" (or, for arguments, their Go type)
+	Want, Got string
+}
+
+func (e ErrTypeMismatch) Error() string {
+	return fmt.Sprintf("type mismatch in parametrized string: %s at offset %d wants a %s, got a %s", e.Op, e.Offset, e.Want, e.Got)
+}
+
+// ErrBadOp is a problem with an operation in a parametrized string, as
+// found by Analyze.
+type ErrBadOp struct {
+	// the operation, e.g. "%e", and where it is in the string
+	Op     string
+	Offset int
+	Err    error
+}
+
+func (e ErrBadOp) Error() string {
+	return fmt.Sprintf("bad parametrized string: %s at offset %d: %v", e.Op, e.Offset, e.Err)
+}
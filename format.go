@@ -0,0 +1,172 @@
+package terminfo
+
+import (
+	"math"
+	"strconv"
+)
+
+// maxWidth is the largest width or precision a format can have; ncurses
+// has the same limit.
+const maxWidth = 10000
+
+type formatFlags byte
+
+const (
+	flagMinus formatFlags = 1 << iota // left-justify
+	flagSpace                         // a space before positive numbers
+	flagAlt                           // 0x or a leading 0
+	flagZero                          // pad with zeros
+)
+
+// format is the [[:]flags][width[.precision]] of a %d, %o, %x, %X, %s or
+// %c, as described in terminfo(5). %c ignores it.
+type format struct {
+	flags formatFlags
+	width int
+	// -1 if there's none
+	prec int
+}
+
+// parseFormat parses the format that may come after a '%' at tpl[i-1],
+// and returns it along with where it ends. As in ncurses, a '-' is only
+// a flag after a ':' (so "%-" is a subtraction and "%:-5d" a
+// left-justified number), and the flags can come in any order.
+func parseFormat(tpl []byte, i int) (format, int, error) {
+	f := format{prec: -1}
+	colon := false
+	for ; i < len(tpl); i++ {
+		switch c := tpl[i]; {
+		case c == ':':
+			colon = true
+		case c == '-' && colon:
+			f.flags |= flagMinus
+		case c == ' ':
+			f.flags |= flagSpace
+		case c == '#':
+			f.flags |= flagAlt
+		case c == '.':
+			if f.prec >= 0 {
+				return f, i, ErrBadParametrizedString
+			}
+			f.prec = 0
+		case c >= '0' && c <= '9':
+			n := &f.width
+			if f.prec >= 0 {
+				n = &f.prec
+			} else if c == '0' && f.width == 0 {
+				f.flags |= flagZero
+				continue
+			}
+			*n = *n*10 + int(c-'0')
+			if *n > maxWidth {
+				return f, i, ErrBadParametrizedString
+			}
+		default:
+			return f, i, nil
+		}
+	}
+	return f, i, nil
+}
+
+// pad appends the padding for n bytes of output.
+func (f format) pad(buf []byte, n int) []byte {
+	for ; n < f.width; n++ {
+		buf = append(buf, ' ')
+	}
+	return buf
+}
+
+// appendString appends s, formatted as by printf's %s.
+func (f format) appendString(buf []byte, s string) []byte {
+	if f.prec >= 0 && f.prec < len(s) {
+		s = s[:f.prec]
+	}
+	if f.flags&flagMinus == 0 {
+		buf = f.pad(buf, len(s))
+	}
+	buf = append(buf, s...)
+	if f.flags&flagMinus != 0 {
+		buf = f.pad(buf, len(s))
+	}
+	return buf
+}
+
+// appendInt appends n, formatted as by printf's %d, %o, %x or %X.
+// Negative numbers are unsigned ints to %o, %x and %X, as in C, so that
+// -1 is ffffffff.
+func (f format) appendInt(buf []byte, verb byte, n int) []byte {
+	var u uint64
+	switch {
+	case verb == 'd' && n < 0:
+		u = uint64(-n)
+	case n < 0 && n >= math.MinInt32:
+		u = uint64(uint32(n))
+	default:
+		u = uint64(n)
+	}
+	base, prefix := 10, ""
+	switch verb {
+	case 'd':
+		if n < 0 {
+			prefix = "-"
+		} else if f.flags&flagSpace != 0 {
+			prefix = " "
+		}
+	case 'o':
+		base = 8
+	case 'x', 'X':
+		base = 16
+		if f.flags&flagAlt != 0 && u != 0 {
+			prefix = "0x"
+		}
+	}
+
+	var digits [24]byte
+	d := strconv.AppendUint(digits[:0], u, base)
+	if f.prec == 0 && u == 0 {
+		// as in C, no digits at all
+		d = d[:0]
+	}
+	zeros := 0
+	if f.prec > len(d) {
+		zeros = f.prec - len(d)
+	}
+	if verb == 'o' && f.flags&flagAlt != 0 && zeros == 0 && (len(d) == 0 || d[0] != '0') {
+		zeros = 1
+	}
+	n = len(prefix) + zeros + len(d)
+	if f.flags&(flagZero|flagMinus) == flagZero && f.prec < 0 && n < f.width {
+		zeros += f.width - n
+		n = f.width
+	}
+
+	if f.flags&flagMinus == 0 {
+		buf = f.pad(buf, n)
+	}
+	if verb == 'X' && prefix == "0x" {
+		prefix = "0X"
+	}
+	buf = append(buf, prefix...)
+	for ; zeros > 0; zeros-- {
+		buf = append(buf, '0')
+	}
+	for _, c := range d {
+		if verb == 'X' && c >= 'a' {
+			c -= 'a' - 'A'
+		}
+		buf = append(buf, c)
+	}
+	if f.flags&flagMinus != 0 {
+		buf = f.pad(buf, n)
+	}
+	return buf
+}
+
+// appendChar appends n as by ncurses' %c: as a byte, but with 0 sent as
+// 0200 so that it doesn't end the string.
+func appendChar(buf []byte, n int) []byte {
+	if n == 0 {
+		return append(buf, 0200)
+	}
+	return append(buf, byte(n))
+}
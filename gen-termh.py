@@ -1,28 +1,78 @@
 #!/usr/bin/python3
-# NOTE this is probably wrong-headed
+# Generates termh.go from ncurses' table of capabilities, include/Caps
+# in its source tree, whose path is given as the only argument. go
+# generate passes it $NCURSES_CAPS, e.g.
+#
+#   NCURSES_CAPS=$HOME/src/ncurses/include/Caps go generate
+#
+# and if that's not set, ../ncurses/include/Caps is used, for an ncurses
+# tree checked out next to this one.
 
 import contextlib
 import os
 import re
+import sys
 
-# note the sneaky s and the end of type
-match = re.compile(r"^#define\s+(?P<name>\S+)\s+CUR\s+(?P<type>\w+)s\[(?P<index>\d+)\]\s*$").match
+# variable name, capname, type, termcap code, and then some more
+# fields we don't care about
+match = re.compile(r"^(?P<name>[a-z]\w*)\s+(?P<capname>\S+)\s+(?P<type>bool|num|str)\s+(?P<termcap>\S+)\s").match
 py2cc_sub = re.compile(r"(?:^|_)(.)").sub
 
 def camelcase(s):
     return py2cc_sub(lambda m: m.group(1).upper(), s)
 
-attrs = dict(Boolean=[], Number=[], String=[])
-for line in open("/usr/include/term.h"):
+types = dict(bool="Boolean", num="Number", str="String")
+
+examples = dict(
+    String=("CursorAddress", "cup", "cm"),
+    Number=("Columns", "cols", "co"),
+    Boolean=("AutoRightMargin", "am", "am"),
+)
+
+methods = """
+// CapName returns the capability's short name, as used in terminfo
+// source, e.g. "{example[1]}" for {example[0]}.
+func (i {typ}Index) CapName() string {{
+	if i < 0 || i > Max{typ}Index {{
+		return ""
+	}}
+	return {lower}CapNames[i]
+}}
+
+// TermcapName returns the capability's termcap code, e.g. "{example[2]}" for
+// {example[0]}, or "" if it doesn't have one.
+func (i {typ}Index) TermcapName() string {{
+	if i < 0 || i > Max{typ}Index {{
+		return ""
+	}}
+	return {lower}TermcapNames[i]
+}}
+
+// Lookup{typ} returns the index of the {lower} capability with the
+// given capname, e.g. {example[0]} for "{example[1]}".
+func Lookup{typ}(capname string) ({typ}Index, bool) {{
+	i, ok := {lower}sByCapName[capname]
+	return i, ok
+}}
+
+// LookupTermcap{typ} returns the index of the {lower} capability with
+// the given termcap code, e.g. {example[0]} for "{example[2]}".
+func LookupTermcap{typ}(code string) ({typ}Index, bool) {{
+	i, ok := {lower}sByTermcapName[code]
+	return i, ok
+}}"""
+
+caps = sys.argv[1] if len(sys.argv) > 1 and sys.argv[1] else "../ncurses/include/Caps"
+if not os.path.exists(caps):
+    sys.exit("%s: %s not found; set $NCURSES_CAPS to the path of ncurses' include/Caps" % (sys.argv[0], caps))
+
+attrs = dict(String=[], Number=[], Boolean=[])
+for line in open(caps):
     m = match(line)
     if m is None:
         continue
     d = m.groupdict()
-    tl = attrs[d["type"]]
-    idx = int(d["index"])
-    while len(tl) <= idx:
-        tl.append("")
-    tl[idx] = camelcase(d["name"])
+    attrs[types[d["type"]]].append((camelcase(d["name"]), d["capname"], d["termcap"]))
 
 fn = os.path.join(os.path.dirname(__file__), "termh.go")
 with contextlib.redirect_stdout(open(fn, "w")):
@@ -33,8 +83,32 @@ with contextlib.redirect_stdout(open(fn, "w")):
     for typ, bs in attrs.items():
         print("type {}Index int".format(typ))
         print("const (")
-        print("\t{} {}Index = iota".format(bs[0], typ))
-        for b in bs[1:]:
+        print("\t{} {}Index = iota".format(bs[0][0], typ))
+        for b, _, _ in bs[1:]:
             print("\t{}".format(b))
-        print("\tMax{}Index = {}".format(typ, b))
+        print("\tMax{}Index = {}".format(typ, bs[-1][0]))
         print(")")
+        print("var {}CapNames = [...]string{{".format(typ.lower()))
+        for b, capname, _ in bs:
+            print('\t{}: "{}",'.format(b, capname))
+        print("}")
+        # "-" means there's no termcap equivalent
+        print("var {}TermcapNames = [...]string{{".format(typ.lower()))
+        for b, _, termcap in bs:
+            print('\t{}: "{}",'.format(b, "" if termcap == "-" else termcap))
+        print("}")
+        print("var {}sByCapName = map[string]{}Index{{".format(typ.lower(), typ))
+        for b, capname, _ in bs:
+            print('\t"{}": {},'.format(capname, b))
+        print("}")
+        print("var {}sByTermcapName = map[string]{}Index{{".format(typ.lower(), typ))
+        seen = set()
+        for b, _, termcap in bs:
+            # a few codes are shared (ML is both smgl and smglr); the
+            # first one wins, as in ncurses
+            if termcap == "-" or termcap in seen:
+                continue
+            seen.add(termcap)
+            print('\t"{}": {},'.format(termcap, b))
+        print("}")
+        print(methods.format(typ=typ, lower=typ.lower(), example=examples[typ]))
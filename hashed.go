@@ -0,0 +1,387 @@
+package terminfo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ncurses built with --with-hashed-db keeps its entries in a Berkeley
+// DB 1.85 hash file, keyed by terminal name. What follows is just
+// enough of that format to look things up in one.
+
+const (
+	hashMagic      = 0x061561
+	hashVersion    = 2
+	hashOldVersion = 1
+	// the key the hash function is checked against when opening,
+	// with the NUL that db 1.85 hashes along with it
+	hashCharKey = "%$sniglet^&\x00"
+
+	// these go where a data offset would normally be in a page's
+	// index, to say it's not a regular key/data pair.
+	ovflPage    = 0
+	partialKey  = 1
+	fullKey     = 2
+	fullKeyData = 3
+	realKey     = 4
+
+	// overflow page addresses are a split point and a page number
+	// within it.
+	splitShift = 11
+	splitMask  = 0x7ff
+
+	// an entry in the database is either a compiled entry...
+	hashedEntry = 0
+	// ... or an alias, pointing to the key for the entry proper.
+	hashedAlias = 2
+)
+
+// hashHeader is the header of a Berkeley DB 1.85 hash file (its
+// HASHHDR). Unlike the pages it is always stored big endian.
+type hashHeader struct {
+	Magic     int32
+	Version   int32
+	Lorder    uint32
+	Bsize     int32
+	Bshift    int32
+	Dsize     int32
+	Ssize     int32
+	Sshift    int32
+	OvflPoint int32
+	LastFreed int32
+	MaxBucket uint32
+	HighMask  uint32
+	LowMask   uint32
+	Ffactor   int32
+	Nkeys     int32
+	HdrPages  int32
+	HCharKey  uint32
+	Spares    [32]int32
+	Bitmaps   [32]uint16
+}
+
+type hashDB struct {
+	r        io.ReaderAt
+	filename string
+	hdr      hashHeader
+	order    binary.ByteOrder
+}
+
+// hashedName returns the name ncurses would give a hashed database
+// for the given search path entry.
+func hashedName(path string) string {
+	if strings.HasSuffix(path, ".db") {
+		return path
+	}
+	return path + ".db"
+}
+
+func isRegular(filename string) bool {
+	fi, err := os.Stat(filename)
+	return err == nil && fi.Mode().IsRegular()
+}
+
+// hash4 is Chris Torek's hash function, Berkeley DB's default.
+func hash4(key []byte) uint32 {
+	var h uint32
+	for _, c := range key {
+		h = h<<5 + h + uint32(c)
+	}
+	return h
+}
+
+func log2(n uint32) uint32 {
+	var i uint32
+	for limit := uint32(1); limit < n; limit <<= 1 {
+		i++
+	}
+	return i
+}
+
+func openHashDB(r io.ReaderAt, filename string) (*hashDB, error) {
+	db := &hashDB{r: r, filename: filename}
+	hdr := io.NewSectionReader(r, 0, int64(binary.Size(db.hdr)))
+	if err := binary.Read(hdr, binary.BigEndian, &db.hdr); err != nil {
+		return nil, db.bad("header", err)
+	}
+	if db.hdr.Magic != hashMagic {
+		return nil, db.bad("magic", fmt.Errorf("expected %#x, got %#x", hashMagic, db.hdr.Magic))
+	}
+	if db.hdr.Version != hashVersion && db.hdr.Version != hashOldVersion {
+		return nil, db.bad("version", fmt.Errorf("expected %d, got %d", hashVersion, db.hdr.Version))
+	}
+	switch db.hdr.Lorder {
+	case 1234:
+		db.order = binary.LittleEndian
+	case 4321:
+		db.order = binary.BigEndian
+	default:
+		return nil, db.bad("byte order", fmt.Errorf("expected 1234 or 4321, got %d", db.hdr.Lorder))
+	}
+	if db.hdr.Bshift < 8 || db.hdr.Bshift > 16 || db.hdr.Bsize != 1<<uint(db.hdr.Bshift) {
+		return nil, db.bad("page size", fmt.Errorf("%d is not 1<<%d", db.hdr.Bsize, db.hdr.Bshift))
+	}
+	if h := hash4([]byte(hashCharKey)); h != db.hdr.HCharKey {
+		return nil, db.bad("hash function", fmt.Errorf("expected %#x, got %#x", h, db.hdr.HCharKey))
+	}
+
+	return db, nil
+}
+
+func (db *hashDB) bad(thing string, err error) error {
+	return &ErrBadThing{Thing: "hashed database " + thing, Filename: db.filename, Err: err}
+}
+
+// hashPage is a page of the database. It starts with an index of
+// 16-bit words: their count n, then n words that are mostly pairs of
+// key and data offsets, then the free space left and the offset of the
+// last thing stored in the page. Keys and data are stored from the end
+// of the page backwards.
+type hashPage struct {
+	buf   []byte
+	order binary.ByteOrder
+}
+
+func (p hashPage) word(i int) int {
+	if 2*i+2 > len(p.buf) {
+		return -1
+	}
+	return int(p.order.Uint16(p.buf[2*i:]))
+}
+
+func (p hashPage) n() int         { return p.word(0) }
+func (p hashPage) freeSpace() int { return p.word(p.n() + 1) }
+
+// slice returns the bytes between offsets from and to, checking
+// they're sane.
+func (p hashPage) slice(from, to int) ([]byte, error) {
+	if from < 0 || from > to || to > len(p.buf) {
+		return nil, fmt.Errorf("bad offsets %d:%d", from, to)
+	}
+	return p.buf[from:to], nil
+}
+
+func (db *hashDB) readPage(n uint32) (hashPage, error) {
+	buf := make([]byte, db.hdr.Bsize)
+	if _, err := db.r.ReadAt(buf, int64(n)<<uint(db.hdr.Bshift)); err != nil {
+		return hashPage{}, db.bad("page", err)
+	}
+	p := hashPage{buf: buf, order: db.order}
+	if m := p.n(); m < 0 || 2*(m+3) > len(buf) {
+		return hashPage{}, db.bad("page", fmt.Errorf("page %d has %d entries", n, m))
+	}
+	return p, nil
+}
+
+func (db *hashDB) bucketPage(bucket uint32) uint32 {
+	n := bucket + uint32(db.hdr.HdrPages)
+	if bucket != 0 {
+		n += uint32(db.hdr.Spares[(log2(bucket+1)-1)%32])
+	}
+	return n
+}
+
+// readOvfl reads the overflow page at the given address, as found in
+// the index of other pages.
+func (db *hashDB) readOvfl(addr int) (hashPage, error) {
+	split := uint32(addr) >> splitShift
+	return db.readPage(db.bucketPage(1<<split-1) + uint32(addr)&splitMask)
+}
+
+// maxPages bounds how many pages are looked at for a single key, so
+// a corrupt database can't send us round in circles.
+const maxPages = 1 << 16
+
+var errHashLoop = errors.New("too many overflow pages")
+
+// get looks key up in the database, and returns the associated data
+// or nil if there is none.
+func (db *hashDB) get(key []byte) ([]byte, error) {
+	bucket := hash4(key) & db.hdr.HighMask
+	if bucket > db.hdr.MaxBucket {
+		bucket &= db.hdr.LowMask
+	}
+	p, err := db.readPage(db.bucketPage(bucket))
+	if err != nil {
+		return nil, err
+	}
+
+	seen := 0
+	off := len(p.buf)
+	for ndx := 1; ndx <= p.n(); ndx += 2 {
+		k, d := p.word(ndx), p.word(ndx+1)
+		switch {
+		case d >= realKey:
+			// regular key/data pair
+			buf, err := p.slice(k, off)
+			if err != nil {
+				return nil, db.bad("page", err)
+			}
+			if bytes.Equal(buf, key) {
+				buf, err := p.slice(d, k)
+				if err != nil {
+					return nil, db.bad("page", err)
+				}
+				return buf, nil
+			}
+			off = d
+			continue
+		case d == ovflPage:
+			// the rest is in an overflow page
+			p, err = db.readOvfl(k)
+		default:
+			// a pair too big for a page, which spans several
+			// on its own.
+			var data []byte
+			var next int
+			data, next, err = db.bigPair(p, ndx, key)
+			if err != nil || data != nil {
+				return data, err
+			}
+			if next == 0 {
+				return nil, nil
+			}
+			p, err = db.readOvfl(next)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if seen++; seen > maxPages {
+			return nil, db.bad("page", errHashLoop)
+		}
+		ndx = -1
+		off = len(p.buf)
+	}
+
+	return nil, nil
+}
+
+// bigPair checks whether the big key/data pair starting at index ndx of
+// page p has the given key. If it does it returns the data; otherwise
+// it returns the address of the overflow page that follows the pair
+// (or 0 if there are none).
+//
+// The key is spread over the end of as many pages as needed, each
+// marked partialKey, and each with the address of the next page after
+// the marker. The last page of the key is marked fullKey if the data
+// starts on the next page, or fullKeyData if the data starts in this
+// same page, at the offset given in the last word of the index. The
+// data then follows the same way, with its last page marked
+// fullKeyData and having at least one byte of free space.
+func (db *hashDB) bigPair(p hashPage, ndx int, key []byte) ([]byte, int, error) {
+	var bigKey, data []byte
+	seen := 0
+	for {
+		if seen++; seen > maxPages {
+			return nil, 0, db.bad("page", errHashLoop)
+		}
+		n := p.n()
+		off := p.word(ndx)
+		buf, err := p.slice(off, len(p.buf))
+		if err != nil {
+			return nil, 0, db.bad("page", err)
+		}
+		marker := p.word(ndx + 1)
+		switch {
+		case data == nil && marker == partialKey:
+			bigKey = append(bigKey, buf...)
+		case data == nil:
+			// the key ends here
+			bigKey = append(bigKey, buf...)
+			if !bytes.Equal(bigKey, key) {
+				next, err := db.skipBigPair(p)
+				return nil, next, err
+			}
+			data = []byte{}
+			if marker == fullKeyData {
+				// data starts on this page, ending where the
+				// key begins
+				buf, err := p.slice(p.word(n), off)
+				if err != nil {
+					return nil, 0, db.bad("page", err)
+				}
+				data = append(data, buf...)
+				if p.freeSpace() > 0 {
+					return data, 0, nil
+				}
+			}
+		default:
+			data = append(data, buf...)
+			if marker == fullKeyData {
+				return data, 0, nil
+			}
+		}
+		if n < 4 {
+			return nil, 0, db.bad("page", errors.New("big pair ends early"))
+		}
+		if p, err = db.readOvfl(p.word(3)); err != nil {
+			return nil, 0, err
+		}
+		ndx = 1
+	}
+}
+
+// skipBigPair finds the last page of the big pair that includes page p,
+// and returns the address of the page following it, if any.
+func (db *hashDB) skipBigPair(p hashPage) (int, error) {
+	for seen := 0; ; seen++ {
+		if seen > maxPages {
+			return 0, db.bad("page", errHashLoop)
+		}
+		n := p.n()
+		if p.word(2) == fullKeyData && (n == 2 || p.word(n) == ovflPage || p.freeSpace() > 0) {
+			break
+		}
+		var err error
+		if p, err = db.readOvfl(p.word(n - 1)); err != nil {
+			return 0, err
+		}
+	}
+	if p.n() > 2 {
+		return p.word(3), nil
+	}
+	return 0, nil
+}
+
+// loadHashed loads the named terminal's entry from the hashed
+// database in filename.
+func loadHashed(filename string, term string) (*TermInfo, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	db, err := openHashDB(f, filename)
+	if err != nil {
+		return nil, err
+	}
+
+	key := []byte(term)
+	// the number of hops is arbitrary; aliases point straight at
+	// the entry.
+	for i := 0; i < 4; i++ {
+		data, err := db.get(key)
+		if err != nil {
+			return nil, err
+		}
+		if len(data) == 0 {
+			return nil, &os.PathError{Op: "lookup", Path: filename + ":" + term, Err: os.ErrNotExist}
+		}
+		switch data[0] {
+		case hashedEntry:
+			return parse(bytes.NewReader(data[1:]), filename)
+		case hashedAlias:
+			key = bytes.TrimRight(data[1:], "\x00")
+		default:
+			return nil, db.bad("entry", fmt.Errorf("unknown entry type %d for %q", data[0], key))
+		}
+	}
+
+	return nil, db.bad("entry", fmt.Errorf("too many aliases for %q", term))
+}
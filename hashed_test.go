@@ -0,0 +1,33 @@
+package terminfo_test
+
+import (
+	"os"
+
+	"gopkg.in/check.v1"
+)
+
+func (*tiSuite) TestHashed(c *check.C) {
+	// Berkeley DB 1.85's hash code (as kept in NSS's legacy dbm
+	// library) wrote these, little and big endian, with what tic
+	// --with-hashed-db stores for the directory tree entries in
+	// testdata, and with small pages so the entries themselves don't
+	// fit in one; see testdata/gen-hashed.py.
+	for _, db := range []string{"testdata/terminfo.db", "testdata/terminfo-be.db"} {
+		for term, entry := range map[string]string{
+			"ext-test":   "ext-test",
+			"ext":        "ext-test",
+			"ext-direct": "ext-direct",
+		} {
+			comment := check.Commentf("%s in %s", term, db)
+			ti, err := loadFrom(db, term)
+			c.Assert(err, check.IsNil, comment)
+			c.Check(ti, check.DeepEquals, loadTestdata(c, entry), comment)
+		}
+
+		// descriptions aren't keys
+		for _, term := range []string{"no-such-terminal", "a terminal with some extended capabilities"} {
+			_, err := loadFrom(db, term)
+			c.Check(os.IsNotExist(err), check.Equals, true, check.Commentf("%s: %v", db, err))
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package terminfo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Marshal encodes the TermInfo as a compiled terminfo entry, in the
+// format described in term(5) and read by Parse. The legacy format
+// (magic 0432) is used unless a number doesn't fit in 16 bits, in which
+// case the 32-bit one (magic 01036) is.
+func (ti *TermInfo) Marshal() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := ti.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteTo writes the TermInfo to w as a compiled terminfo entry. See
+// Marshal.
+func (ti *TermInfo) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	put := func(x interface{}) {
+		// writes to a bytes.Buffer don't fail
+		binary.Write(&buf, binary.LittleEndian, x)
+	}
+	pad := func() {
+		if buf.Len()&1 != 0 {
+			buf.WriteByte(0)
+		}
+	}
+
+	nums := ti.numbers()
+	isBig := false
+	for _, n := range nums {
+		isBig = isBig || n > math.MaxInt16
+	}
+	for _, n := range ti.ExtNumbers {
+		isBig = isBig || n > math.MaxInt16
+	}
+
+	names := strings.Join(ti.Names, "|")
+	if strings.IndexByte(names, 0) >= 0 {
+		return 0, &ErrBadThing{Thing: "names section", Err: fmt.Errorf("null in names %q", names)}
+	}
+
+	var numStrings int
+	for idx := range ti.Strings {
+		if idx < 0 {
+			return 0, &ErrBadThing{Thing: "strings section", Err: fmt.Errorf("bad index %d", idx)}
+		}
+		if int(idx) >= numStrings {
+			numStrings = int(idx) + 1
+		}
+	}
+	strIndexes, strTable, err := stringTable(numStrings, func(i int) ([]byte, bool) {
+		str, ok := ti.Strings[StringIndex(i)]
+		return str, ok
+	})
+	if err != nil {
+		return 0, &ErrBadThing{Thing: "strings section", Err: err}
+	}
+
+	header := [6]int{0432, len(names) + 1, len(ti.Booleans), len(nums), numStrings, len(strTable)}
+	if isBig {
+		header[0] = 01036
+	}
+	for i, n := range header {
+		if n > math.MaxInt16 {
+			err := fmt.Errorf("size %d of field %d doesn't fit", n, i+1)
+			return 0, &ErrBadThing{Thing: "header", Err: err}
+		}
+		put(int16(n))
+	}
+
+	buf.WriteString(names)
+	buf.WriteByte(0)
+	for _, b := range ti.Booleans {
+		buf.WriteByte(boolByte(b))
+	}
+	pad()
+	putNumbers(put, nums, isBig)
+	put(strIndexes)
+	buf.Write(strTable)
+
+	if len(ti.ExtBooleans)+len(ti.ExtNumbers)+len(ti.ExtStrings) > 0 {
+		pad()
+		if err := ti.writeExtended(&buf, put, isBig); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := buf.WriteTo(w)
+	return n, err
+}
+
+// numbers returns whichever of Numbers and BigNumbers is in use, as
+// int32s.
+func (ti *TermInfo) numbers() []int32 {
+	if len(ti.BigNumbers) > 0 {
+		return ti.BigNumbers
+	}
+	nums := make([]int32, len(ti.Numbers))
+	for i, n := range ti.Numbers {
+		nums[i] = int32(n)
+	}
+	return nums
+}
+
+func putNumbers(put func(interface{}), nums []int32, isBig bool) {
+	if isBig {
+		put(nums)
+		return
+	}
+	small := make([]int16, len(nums))
+	for i, n := range nums {
+		small[i] = int16(n)
+	}
+	put(small)
+}
+
+func boolByte(b bool) byte {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// stringTable builds the offsets and the table for n strings, as
+// returned by get; absent strings get an offset of -1.
+func stringTable(n int, get func(int) ([]byte, bool)) ([]int16, []byte, error) {
+	indexes := make([]int16, n)
+	var table []byte
+	for i := range indexes {
+		str, ok := get(i)
+		if !ok {
+			indexes[i] = -1
+			continue
+		}
+		if bytes.IndexByte(str, 0) >= 0 {
+			return nil, nil, fmt.Errorf("null in string %d (%q)", i, str)
+		}
+		if len(table) > math.MaxInt16 {
+			return nil, nil, fmt.Errorf("string table too big (%d bytes)", len(table))
+		}
+		indexes[i] = int16(len(table))
+		table = append(table, str...)
+		table = append(table, 0)
+	}
+	return indexes, table, nil
+}
+
+// sortedKeys returns the keys of m, which must be a map with string keys,
+// sorted.
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	switch m := m.(type) {
+	case map[string]bool:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	case map[string]int32:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	case map[string][]byte:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// writeExtended writes the ncurses extended capabilities section; see
+// parse for the layout. Names are sorted within each type, as tic does.
+func (ti *TermInfo) writeExtended(buf *bytes.Buffer, put func(interface{}), isBig bool) error {
+	boolNames := sortedKeys(ti.ExtBooleans)
+	numNames := sortedKeys(ti.ExtNumbers)
+	strNames := sortedKeys(ti.ExtStrings)
+	names := append(append(append([]string(nil), boolNames...), numNames...), strNames...)
+
+	strIndexes, strTable, err := stringTable(len(strNames), func(i int) ([]byte, bool) {
+		return ti.ExtStrings[strNames[i]], true
+	})
+	if err != nil {
+		return &ErrBadThing{Thing: "extended strings section", Err: err}
+	}
+	nameIndexes, nameTable, err := stringTable(len(names), func(i int) ([]byte, bool) {
+		return []byte(names[i]), true
+	})
+	if err != nil {
+		return &ErrBadThing{Thing: "extended names", Err: err}
+	}
+
+	header := [5]int{len(boolNames), len(numNames), len(strNames), len(strNames) + len(names), len(strTable) + len(nameTable)}
+	for i, n := range header {
+		if n > math.MaxInt16 {
+			err := fmt.Errorf("size %d of field %d doesn't fit", n, i+1)
+			return &ErrBadThing{Thing: "extended header", Err: err}
+		}
+		put(int16(n))
+	}
+
+	for _, name := range boolNames {
+		buf.WriteByte(boolByte(ti.ExtBooleans[name]))
+	}
+	if len(boolNames)&1 != 0 {
+		buf.WriteByte(0)
+	}
+	nums := make([]int32, len(numNames))
+	for i, name := range numNames {
+		nums[i] = ti.ExtNumbers[name]
+	}
+	putNumbers(put, nums, isBig)
+	put(strIndexes)
+	put(nameIndexes)
+	buf.Write(strTable)
+	buf.Write(nameTable)
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package terminfo_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math"
+
+	"gopkg.in/check.v1"
+
+	"gopkg.in/terminfo.v0"
+)
+
+func (*tiSuite) TestMarshalLikeTic(c *check.C) {
+	for _, term := range []string{"ext-test", "ext-direct"} {
+		buf, err := ioutil.ReadFile("testdata/e/" + term)
+		c.Assert(err, check.IsNil)
+		ti, err := terminfo.Unmarshal(buf)
+		c.Assert(err, check.IsNil)
+
+		out, err := ti.Marshal()
+		c.Assert(err, check.IsNil)
+		c.Check(out, check.DeepEquals, buf, check.Commentf(term))
+
+		var w bytes.Buffer
+		n, err := ti.WriteTo(&w)
+		c.Assert(err, check.IsNil)
+		c.Check(n, check.Equals, int64(len(buf)))
+		c.Check(w.Bytes(), check.DeepEquals, buf, check.Commentf(term))
+	}
+}
+
+func (*tiSuite) TestMarshalRoundTrip(c *check.C) {
+	ti := loadTestdata(c, "ext-test")
+	ti.ExtBooleans["AX"] = true
+	ti.ExtNumbers["Foo"] = 3
+	ti.ExtStrings["Bar"] = []byte("\x1b[?1h")
+	ti.Strings[terminfo.User9] = []byte("hello")
+	delete(ti.Strings, terminfo.Bell)
+
+	buf, err := ti.Marshal()
+	c.Assert(err, check.IsNil)
+	c.Check(buf[:2], check.DeepEquals, []byte{0x1a, 0x01}) // 0432
+	ti2, err := terminfo.Unmarshal(buf)
+	c.Assert(err, check.IsNil)
+	c.Check(ti2, check.DeepEquals, ti)
+}
+
+func (*tiSuite) TestMarshalPicksFormat(c *check.C) {
+	ti := loadTestdata(c, "ext-test")
+	ti.Numbers[terminfo.MaxColors] = math.MaxInt16
+	buf, err := ti.Marshal()
+	c.Assert(err, check.IsNil)
+	c.Check(buf[:2], check.DeepEquals, []byte{0x1a, 0x01}) // 0432
+
+	// a big extended number is enough to need the 32-bit format
+	ti.ExtNumbers["U8"] = math.MaxInt16 + 1
+	buf, err = ti.Marshal()
+	c.Assert(err, check.IsNil)
+	c.Check(buf[:2], check.DeepEquals, []byte{0x1e, 0x02}) // 01036
+	ti2, err := terminfo.Unmarshal(buf)
+	c.Assert(err, check.IsNil)
+	c.Check(ti2.BigNumbers[terminfo.MaxColors], check.Equals, int32(math.MaxInt16))
+	c.Check(ti2.ExtNumbers["U8"], check.Equals, int32(math.MaxInt16+1))
+
+	// and the other way around
+	ti = loadTestdata(c, "ext-direct")
+	ti.BigNumbers[terminfo.MaxColors] = 256
+	ti.BigNumbers[terminfo.MaxPairs] = 256
+	buf, err = ti.Marshal()
+	c.Assert(err, check.IsNil)
+	c.Check(buf[:2], check.DeepEquals, []byte{0x1a, 0x01}) // 0432
+	ti2, err = terminfo.Unmarshal(buf)
+	c.Assert(err, check.IsNil)
+	c.Check(ti2.Numbers[terminfo.MaxColors], check.Equals, int16(256))
+}
+
+func (*tiSuite) TestMarshalBad(c *check.C) {
+	ti := loadTestdata(c, "ext-test")
+	ti.Strings[terminfo.User9] = []byte("a\x00b")
+	_, err := ti.Marshal()
+	c.Check(err, check.ErrorMatches, `bad strings section in terminfo entry: null in string .*`)
+}
@@ -0,0 +1,124 @@
+package terminfo
+
+import (
+	"image/color"
+	"sync"
+)
+
+// colorPairs hands out the colour pairs of a terminal that can only set
+// colours with InitializePair and SetColorPair. Pair 0 is the terminal's
+// default, as set by OrigPair, so it's pairs 1 to MaxPairs-1 that are
+// given out, and once they all have been, the one used least recently is
+// reused.
+type colorPairs struct {
+	sync.Mutex
+	// the colours last set, for Foreground and Background to keep the
+	// other one of
+	fg, bg color.Color
+	// the pairs given out, by their colours
+	byColors map[[2]color.NRGBA]int
+	// what pair i+1 is set to, and when it was last used
+	colors [][2]color.NRGBA
+	used   []uint64
+	clock  uint64
+}
+
+// get returns the pair for colors, out of n, and whether it's been
+// given out to them just now, and so needs setting up.
+func (p *colorPairs) get(colors [2]color.NRGBA, n int) (int, bool) {
+	p.clock++
+	if pair, ok := p.byColors[colors]; ok {
+		p.used[pair-1] = p.clock
+		return pair, false
+	}
+	if p.byColors == nil {
+		p.byColors = make(map[[2]color.NRGBA]int)
+	}
+	var pair int
+	if len(p.colors) < n-1 {
+		p.colors = append(p.colors, colors)
+		p.used = append(p.used, p.clock)
+		pair = len(p.colors)
+	} else {
+		lru := 0
+		for i, t := range p.used {
+			if t < p.used[lru] {
+				lru = i
+			}
+		}
+		delete(p.byColors, p.colors[lru])
+		p.colors[lru], p.used[lru] = colors, p.clock
+		pair = lru + 1
+	}
+	p.byColors[colors] = pair
+	return pair, true
+}
+
+// usesPairs returns whether the terminal can only set colours with
+// colour pairs.
+func (ti *TermInfo) usesPairs() bool {
+	for _, idx := range []StringIndex{SetAForeground, SetABackground, SetForeground, SetBackground} {
+		if ti.Strings[idx] != nil {
+			return false
+		}
+	}
+	return !ti.hasSetRGB() &&
+		ti.Strings[InitializePair] != nil && ti.Strings[SetColorPair] != nil &&
+		ti.number(MaxPairs) > 1
+}
+
+// ColorPair returns the colour pair for fg and bg, on terminals that use
+// them, and what sets it up (with InitializePair) if it hasn't been yet,
+// or ok = false on terminals that don't. Colour pairs are given out as
+// they're asked for, and once the terminal has none left, the one used
+// least recently is set up anew, changing what's shown in it. As the
+// terminal's default colours can't be given to InitializePair, Default
+// is LightGrey as the foreground and Black as the background.
+func (ti *TermInfo) ColorPair(fg, bg color.Color) (pair int, init string, ok bool) {
+	if ti.ColorDepth() <= 0 || !ti.usesPairs() {
+		return 0, "", false
+	}
+	ti.pairs.Lock()
+	defer ti.pairs.Unlock()
+	pair, init = ti.colorPair(fg, bg)
+	return pair, init, true
+}
+
+// colorPair is ColorPair, with ti.pairs locked.
+func (ti *TermInfo) colorPair(fg, bg color.Color) (int, string) {
+	if isDefault(fg) {
+		fg = LightGrey
+	}
+	if isDefault(bg) {
+		bg = Black
+	}
+	colors := [2]color.NRGBA{
+		color.NRGBAModel.Convert(fg).(color.NRGBA),
+		color.NRGBAModel.Convert(bg).(color.NRGBA),
+	}
+	pair, isNew := ti.pairs.get(colors, ti.number(MaxPairs))
+	if !isNew {
+		return pair, ""
+	}
+	f, b := ti.components(colors[0]), ti.components(colors[1])
+	return pair, ti.MustUnescape(InitializePair, pair, f[0], f[1], f[2], b[0], b[1], b[2])
+}
+
+// pairColor returns what sets the colours with a colour pair, for
+// Color, Foreground and Background: to fg if setFg, and bg if setBg,
+// keeping the others as they were last set.
+func (ti *TermInfo) pairColor(fg, bg color.Color, setFg, setBg bool) string {
+	ti.pairs.Lock()
+	defer ti.pairs.Unlock()
+	if setFg {
+		ti.pairs.fg = fg
+	}
+	if setBg {
+		ti.pairs.bg = bg
+	}
+	if isDefault(ti.pairs.fg) && isDefault(ti.pairs.bg) {
+		return ti.resetColors()
+	}
+	pair, init := ti.colorPair(ti.pairs.fg, ti.pairs.bg)
+	return init + ti.MustUnescape(SetColorPair, pair)
+}
@@ -0,0 +1,62 @@
+package terminfo_test
+
+import (
+	"gopkg.in/check.v1"
+
+	"gopkg.in/terminfo.v0"
+)
+
+// pairTerm returns a TermInfo with 8 colours set with colour pairs, of
+// which it has n.
+func pairTerm(n int) *terminfo.TermInfo {
+	ti := &terminfo.TermInfo{
+		Booleans: make([]bool, terminfo.HueLightnessSaturation+1),
+		Numbers:  make([]int16, terminfo.MaxPairs+1),
+		Strings: map[terminfo.StringIndex][]byte{
+			terminfo.InitializePair: []byte("I%p1%d:%p2%d,%p3%d,%p4%d:%p5%d,%p6%d,%p7%d;"),
+			terminfo.SetColorPair:   []byte("S%p1%d;"),
+			terminfo.OrigPair:       []byte("op;"),
+		},
+	}
+	ti.Numbers[terminfo.MaxColors] = 8
+	ti.Numbers[terminfo.MaxPairs] = int16(n)
+	return ti
+}
+
+func (*tiSuite) TestColorPairs(c *check.C) {
+	defer setenv("COLORTERM", "")()
+
+	ti := pairTerm(3)
+	c.Check(ti.Color(terminfo.Red, terminfo.Black), check.Equals, "I1:804,0,0:0,0,0;S1;")
+	c.Check(ti.Color(terminfo.Red, terminfo.Black), check.Equals, "S1;")
+	c.Check(ti.Foreground(terminfo.Blue), check.Equals, "I2:0,0,934:0,0,0;S2;")
+	c.Check(ti.Color(terminfo.Red, terminfo.Black), check.Equals, "S1;")
+	// out of pairs, so the one least recently used goes
+	c.Check(ti.Background(terminfo.White), check.Equals, "I2:804,0,0:1000,1000,1000;S2;")
+	pair, init, ok := ti.ColorPair(terminfo.Blue, nil)
+	c.Check(pair, check.Equals, 1)
+	c.Check(init, check.Equals, "I1:0,0,934:0,0,0;")
+	c.Check(ok, check.Equals, true)
+	pair, init, ok = ti.ColorPair(terminfo.Blue, terminfo.Black)
+	c.Check(pair, check.Equals, 1)
+	c.Check(init, check.Equals, "")
+	c.Check(ok, check.Equals, true)
+
+	// the defaults are OrigPair's
+	c.Check(ti.ResetColors(), check.Equals, "op;")
+	c.Check(ti.Foreground(nil), check.Equals, "op;")
+	c.Check(ti.Background(terminfo.Blue), check.Equals, "I2:899,899,899:0,0,934;S2;")
+	c.Check(ti.Color(nil, terminfo.Default), check.Equals, "op;")
+
+	// in HLS, on terminals that want it
+	ti = pairTerm(64)
+	ti.Booleans[terminfo.HueLightnessSaturation] = true
+	c.Check(ti.Color(terminfo.LightRed, terminfo.Yellow), check.Equals, "I1:120,50,100:180,50,100;S1;")
+	c.Check(ti.Color(terminfo.DarkGrey, terminfo.Blue), check.Equals, "I2:0,49,0:0,46,100;S2;")
+
+	// and not at all on terminals that don't
+	_, _, ok = loadTestdata(c, "xterm-256color").ColorPair(terminfo.Red, terminfo.Black)
+	c.Check(ok, check.Equals, false)
+	_, _, ok = pairTerm(1).ColorPair(terminfo.Red, terminfo.Black)
+	c.Check(ok, check.Equals, false)
+}
@@ -0,0 +1,94 @@
+package terminfo
+
+import (
+	"image/color"
+	"io"
+	"sort"
+	"sync"
+)
+
+// A Palette changes the colours of a terminal that can (one with
+// CanChange and InitializeColor), and remembers which ones it changed,
+// to put them back when it's closed.
+type Palette struct {
+	ti *TermInfo
+	w  io.Writer
+
+	mu      sync.Mutex
+	changed map[int]bool
+}
+
+// NewPalette returns a Palette that changes the terminal's colours by
+// writing to w, or ErrCantChangeColors if it can't. Only terminals with
+// OrigColors can have their colours put back as they were: on others,
+// Close can only go by what xterm has by default.
+func (ti *TermInfo) NewPalette(w io.Writer) (*Palette, error) {
+	if !ti.flag(CanChange) || ti.Strings[InitializeColor] == nil || ti.number(MaxColors) <= 0 {
+		return nil, ErrCantChangeColors
+	}
+	return &Palette{ti: ti, w: w, changed: make(map[int]bool)}, nil
+}
+
+// Set changes colour i to c, as near as the terminal can get to it: its
+// red, green and blue from 0 to 1000, or its hue, lightness and
+// saturation on terminals with HueLightnessSaturation.
+func (p *Palette) Set(i int, c color.Color) error {
+	if i < 0 || i >= p.ti.number(MaxColors) {
+		return ErrNoSuchColor
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.set(i, c); err != nil {
+		return err
+	}
+	p.changed[i] = true
+	return nil
+}
+
+func (p *Palette) set(i int, c color.Color) error {
+	v := p.ti.components(c)
+	_, err := p.ti.Fputs(p.w, InitializeColor, 1, i, v[0], v[1], v[2])
+	return err
+}
+
+// Close puts back the colours the Palette changed: all of them, with
+// OrigColors, on terminals that have it, or else each one it changed,
+// to what xterm has by default. It returns ErrCantRestoreColors if
+// some of them are past the end of xterm's, and so can't be.
+func (p *Palette) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.changed) == 0 {
+		return nil
+	}
+	if p.ti.Strings[OrigColors] != nil {
+		if _, err := p.ti.Fputs(p.w, OrigColors, 1); err != nil {
+			return err
+		}
+		p.changed = make(map[int]bool)
+		return nil
+	}
+
+	var changed []int
+	for i := range p.changed {
+		changed = append(changed, i)
+	}
+	sort.Ints(changed)
+	palette := xterm
+	switch cols := p.ti.number(MaxColors); {
+	case cols >= 256:
+		palette = xterm256
+	case cols >= 88:
+		palette = xterm88
+	}
+	var err error
+	for _, i := range changed {
+		if i >= len(palette) {
+			err = ErrCantRestoreColors
+		} else if err := p.set(i, palette[i]); err != nil {
+			return err
+		}
+		delete(p.changed, i)
+	}
+	return err
+}
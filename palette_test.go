@@ -0,0 +1,76 @@
+package terminfo_test
+
+import (
+	"bytes"
+	"image/color"
+
+	"gopkg.in/check.v1"
+
+	"gopkg.in/terminfo.v0"
+)
+
+func (*tiSuite) TestPalette(c *check.C) {
+	ti := loadTestdata(c, "xterm-256color")
+	var buf bytes.Buffer
+	p, err := ti.NewPalette(&buf)
+	c.Assert(err, check.IsNil)
+
+	// nothing changed, nothing to put back
+	c.Check(p.Close(), check.IsNil)
+	c.Check(buf.String(), check.Equals, "")
+
+	c.Check(p.Set(1, terminfo.Red), check.IsNil)
+	c.Check(p.Set(255, color.RGBA{0x12, 0x34, 0x56, 0xff}), check.IsNil)
+	c.Check(buf.String(), check.Equals, "\x1b]4;1;rgb:CD/00/00\x1b\\\x1b]4;255;rgb:12/34/56\x1b\\")
+	c.Check(p.Set(256, terminfo.Red), check.Equals, terminfo.ErrNoSuchColor)
+	c.Check(p.Set(-1, terminfo.Red), check.Equals, terminfo.ErrNoSuchColor)
+
+	// xterm has oc, to put them all back
+	buf.Reset()
+	c.Check(p.Close(), check.IsNil)
+	c.Check(buf.String(), check.Equals, "\x1b]104\a")
+
+	// without it, they're put back one by one
+	delete(ti.Strings, terminfo.OrigColors)
+	c.Check(p.Set(255, terminfo.Red), check.IsNil)
+	c.Check(p.Set(1, terminfo.Blue), check.IsNil)
+	c.Check(p.Set(1, terminfo.Green), check.IsNil)
+	buf.Reset()
+	c.Check(p.Close(), check.IsNil)
+	c.Check(buf.String(), check.Equals, "\x1b]4;1;rgb:CD/00/00\x1b\\\x1b]4;255;rgb:EE/EE/EE\x1b\\")
+	buf.Reset()
+	c.Check(p.Close(), check.IsNil)
+	c.Check(buf.String(), check.Equals, "")
+
+	// in HLS, for terminals that want it
+	ti = colorTerm(8)
+	ti.Booleans = make([]bool, terminfo.HueLightnessSaturation+1)
+	ti.Booleans[terminfo.CanChange] = true
+	ti.Booleans[terminfo.HueLightnessSaturation] = true
+	ti.Strings[terminfo.InitializeColor] = []byte("c%p1%d:%p2%d,%p3%d,%p4%d;")
+	buf.Reset()
+	p, err = ti.NewPalette(&buf)
+	c.Assert(err, check.IsNil)
+	c.Check(p.Set(2, terminfo.LightGreen), check.IsNil)
+	c.Check(p.Set(4, color.RGBA{204, 76, 25, 255}), check.IsNil)
+	c.Check(p.Close(), check.IsNil)
+	c.Check(buf.String(), check.Equals, "c2:240,50,100;c4:137,44,77;c2:240,40,100;c4:0,46,100;")
+
+	// but what's past xterm's colours can't be put back
+	ti.Numbers[terminfo.MaxColors] = 100
+	p, err = ti.NewPalette(&buf)
+	c.Assert(err, check.IsNil)
+	c.Check(p.Set(90, terminfo.Red), check.IsNil)
+	c.Check(p.Set(1, terminfo.Red), check.IsNil)
+	buf.Reset()
+	c.Check(p.Close(), check.Equals, terminfo.ErrCantRestoreColors)
+	c.Check(buf.String(), check.Equals, "c1:120,40,100;")
+	c.Check(p.Close(), check.IsNil)
+
+	// and none for those that can't
+	ti.Booleans[terminfo.CanChange] = false
+	_, err = ti.NewPalette(&buf)
+	c.Check(err, check.Equals, terminfo.ErrCantChangeColors)
+	_, err = loadTestdata(c, "ext-test").NewPalette(&buf)
+	c.Check(err, check.Equals, terminfo.ErrCantChangeColors)
+}
@@ -0,0 +1,745 @@
+package terminfo
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// opcode is the operation of an instruction in a compiled parametrized
+// string.
+type opcode byte
+
+const (
+	opText   opcode = iota // output text
+	opPrintf               // pop a value and output it as %arg, per f
+	opParam                // push parameter arg
+	opConst                // push arg
+	opSet                  // pop a value into variable arg
+	opGet                  // push the value of variable arg
+	opLen                  // pop a string and push its length
+	opAdd
+	opSub
+	opMul
+	opDiv
+	opMod
+	opAnd
+	opOr
+	opXor
+	opEq
+	opLt
+	opGt
+	opLogicalAnd
+	opLogicalOr
+	opNot
+	opComplement
+	opIncr      // %i
+	opJumpFalse // pop a value and jump to arg if it's false
+	opJump      // jump to arg
+	opIf        // %?; does nothing
+	opEndIf     // %;; does nothing
+	opNop       // an operation ncurses ignores; does nothing
+)
+
+// insn is an instruction in a compiled parametrized string.
+type insn struct {
+	op  opcode
+	arg int
+	// the text to output for opText
+	text []byte
+	// the format for opPrintf
+	f format
+	// where the instruction came from in the string
+	pos, end int
+}
+
+// A Program is a compiled parametrized string, ready to be evaluated as
+// many times as needed without parsing it again.
+type Program struct {
+	src   []byte
+	insns []insn
+	// the static variables; nil if they only last for one run
+	statics *variables
+
+	// what TParm needs to know: which params are strings (those given
+	// to %s or %l), and whether there are any %p's
+	strParams [9]bool
+	hasParams bool
+}
+
+// value is a number or a string, as found on the stack and in variables.
+// The zero value is the number 0.
+type value struct {
+	num   int
+	str   string
+	isStr bool
+}
+
+// kind describes the value for errors.
+func (v value) kind() string {
+	if v.isStr {
+		return "string"
+	}
+	return "number"
+}
+
+// argValue converts an argument to a value. Integers of any type are
+// numbers, and strings, byte slices and fmt.Stringers are strings.
+func argValue(arg interface{}) (value, bool) {
+	switch arg := arg.(type) {
+	case int:
+		return value{num: arg}, true
+	case int8:
+		return value{num: int(arg)}, true
+	case int16:
+		return value{num: int(arg)}, true
+	case int32:
+		return value{num: int(arg)}, true
+	case int64:
+		return value{num: int(arg)}, true
+	case uint:
+		return value{num: int(arg)}, true
+	case uint8:
+		return value{num: int(arg)}, true
+	case uint16:
+		return value{num: int(arg)}, true
+	case uint32:
+		return value{num: int(arg)}, true
+	case uint64:
+		return value{num: int(arg)}, true
+	case uintptr:
+		return value{num: int(arg)}, true
+	case string:
+		return value{str: arg, isStr: true}, true
+	case []byte:
+		return value{str: string(arg), isStr: true}, true
+	}
+	// types of our own, say a colour that's a uint8, are what they're
+	// made of, even if they're also Stringers
+	switch v := reflect.ValueOf(arg); v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value{num: int(v.Int())}, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return value{num: int(v.Uint())}, true
+	case reflect.String:
+		return value{str: v.String(), isStr: true}, true
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return value{str: string(v.Bytes()), isStr: true}, true
+		}
+	}
+	if arg, ok := arg.(fmt.Stringer); ok {
+		return value{str: arg.String(), isStr: true}, true
+	}
+	return value{}, false
+}
+
+// variables holds the values of the %P/%g variables a..z (dynamic)
+// or A..Z (static). Unset variables read as 0.
+type variables [26]value
+
+// stackSize is how deep the stack can get. Pushing past it is an error,
+// except with TParm, where, as in ncurses, those pushes are dropped.
+const stackSize = 20
+
+// paramStack is the stack of a running Program. Rather than having
+// every operation check how it went, the first error is kept in err, and
+// values popped after that are zeros.
+type paramStack struct {
+	vals [stackSize]value
+	n    int
+	err  error
+	// set to be as lenient as ncurses' tparm
+	tparm bool
+	// the program, and the instruction it's running, for errors
+	p  *Program
+	in *insn
+}
+
+func (s *paramStack) fail(err error) {
+	if s.err == nil {
+		s.err = err
+	}
+}
+
+func (s *paramStack) push(v value) {
+	if s.tparm && !v.isStr {
+		// ncurses' numbers are C ints
+		v.num = int(int32(v.num))
+	}
+	if s.n == len(s.vals) {
+		if !s.tparm {
+			s.fail(&ErrBadOp{Op: s.p.op(s.in), Offset: s.in.pos, Err: ErrStackOverflow})
+		}
+		return
+	}
+	s.vals[s.n] = v
+	s.n++
+}
+
+func (s *paramStack) pushInt(n int) {
+	s.push(value{num: n})
+}
+
+func (s *paramStack) pushBool(b bool) {
+	if b {
+		s.pushInt(1)
+	} else {
+		s.pushInt(0)
+	}
+}
+
+func (s *paramStack) pop() value {
+	if s.n == 0 {
+		if s.tparm {
+			return value{}
+		}
+		s.fail(&ErrStackUnderflow{Offset: s.in.pos, Op: s.p.op(s.in)})
+		return value{}
+	}
+	s.n--
+	return s.vals[s.n]
+}
+
+// popKind pops a value, which must be a string if str is true, and a
+// number otherwise.
+func (s *paramStack) popKind(str bool) value {
+	v := s.pop()
+	if v.isStr != str && s.err == nil {
+		if s.tparm {
+			// ncurses makes numbers of strings, and empty strings
+			// of numbers
+			return value{}
+		}
+		want := value{isStr: str}
+		s.fail(&ErrTypeMismatch{Offset: s.in.pos, Op: s.p.op(s.in), Want: want.kind(), Got: v.kind()})
+		return value{}
+	}
+	return v
+}
+
+func (s *paramStack) popInt() int {
+	return s.popKind(false).num
+}
+
+func (s *paramStack) popBool() bool {
+	return s.popInt() != 0
+}
+
+func (s *paramStack) popString() string {
+	return s.popKind(true).str
+}
+
+// conditional is a %? ... %; being compiled: the jumps of its %t's and
+// %e's that wait for the %e or %; that tells them where to go.
+type conditional struct {
+	thens, elses []int
+	// where its %? is, for errors
+	pos, end int
+}
+
+// Compile parses the parametrized string tpl, as described in
+// terminfo(5), into a Program. Static variables only last for the
+// duration of each run of it; use TermInfo.Program to have them persist
+// across runs.
+func Compile(tpl []byte) (*Program, error) {
+	p, err := compile(tpl, nil)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// compile does the work of Compile. If report isn't nil, it's told
+// where errors are, and about the conditionals and operations that
+// ncurses (and so Compile) makes do with but that aren't right: then the
+// program is returned even if there's an error, as far as it got.
+func compile(tpl []byte, report func(pos, end int, err error)) (*Program, error) {
+	// the program refers to bits of tpl
+	tpl = append([]byte(nil), tpl...)
+	p := &Program{src: tpl}
+	emit := func(op opcode, arg int) {
+		p.insns = append(p.insns, insn{op: op, arg: arg})
+	}
+	// the conditionals being compiled, innermost last. The outermost
+	// one is for a %t without a %? before it.
+	conds := []*conditional{{}}
+	resolve := func(jumps []int) []int {
+		for _, j := range jumps {
+			p.insns[j].arg = len(p.insns)
+		}
+		return jumps[:0]
+	}
+
+	// the operation being compiled is tpl[start:i+1]
+	var start, i int
+	warn := func(err error) {
+		if report != nil {
+			report(start, i+1, err)
+		}
+	}
+	fail := func(err error) (*Program, error) {
+		if i >= len(tpl) {
+			i = len(tpl) - 1
+		}
+		warn(err)
+		return p, err
+	}
+
+	// the %p just before the current operation, for TParm
+	lastParam := 0
+	for ; i < len(tpl); i++ {
+		n := len(p.insns)
+		start = i
+		if tpl[i] != '%' {
+			j := bytes.IndexByte(tpl[i:], '%')
+			if j < 0 {
+				j = len(tpl) - i
+			}
+			p.insns = append(p.insns, insn{op: opText, text: tpl[i : i+j], pos: i, end: i + j})
+			i += j - 1
+			continue
+		}
+		// a format can come before anything, but only the printf-like
+		// operations use it
+		f, j, err := parseFormat(tpl, i+1)
+		if err != nil {
+			i = j
+			return fail(err)
+		}
+		if j == len(tpl) {
+			if j > i+1 {
+				i = j
+				return fail(ErrTruncatedParametrizedString)
+			}
+			// ncurses ignores a % at the end
+			p.insns = append(p.insns, insn{op: opNop, pos: start, end: j})
+			warn(ErrTruncatedParametrizedString)
+			break
+		}
+		i = j
+		prevParam := lastParam
+		lastParam = 0
+		switch c := tpl[i]; c {
+		case '%':
+			p.insns = append(p.insns, insn{op: opText, text: tpl[i : i+1]})
+		case 'd', 'o', 'x', 'X', 's', 'c':
+			p.insns = append(p.insns, insn{op: opPrintf, arg: int(c), f: f})
+			if c == 's' && prevParam > 0 {
+				p.strParams[prevParam-1] = true
+			}
+		case 'p':
+			i++
+			if i == len(tpl) {
+				return fail(ErrTruncatedParametrizedString)
+			}
+			if tpl[i] < '1' || tpl[i] > '9' {
+				return fail(ErrBadParametrizedString)
+			}
+			lastParam = int(tpl[i] - '0')
+			p.hasParams = true
+			emit(opParam, lastParam)
+		case 'P', 'g':
+			i++
+			if i == len(tpl) {
+				return fail(ErrTruncatedParametrizedString)
+			}
+			var n int
+			switch v := tpl[i]; {
+			case v >= 'a' && v <= 'z':
+				n = int(v - 'a')
+			case v >= 'A' && v <= 'Z':
+				// statics come after the dynamics
+				n = len(variables{}) + int(v-'A')
+			default:
+				return fail(ErrBadParametrizedString)
+			}
+			if c == 'P' {
+				emit(opSet, n)
+			} else {
+				emit(opGet, n)
+			}
+		case '\'':
+			i += 2
+			if i >= len(tpl) {
+				return fail(ErrTruncatedParametrizedString)
+			}
+			if tpl[i] != '\'' {
+				return fail(ErrBadParametrizedString)
+			}
+			emit(opConst, int(tpl[i-1]))
+		case '{':
+			j := bytes.IndexByte(tpl[i:], '}')
+			if j < 0 {
+				i = len(tpl)
+				return fail(ErrTruncatedParametrizedString)
+			}
+			digits := tpl[i+1 : i+j]
+			i += j
+			n := 0
+			for _, d := range digits {
+				if d < '0' || d > '9' {
+					return fail(ErrBadParametrizedString)
+				}
+				n = n*10 + int(d-'0')
+			}
+			emit(opConst, n)
+		case 'l':
+			emit(opLen, 0)
+			if prevParam > 0 {
+				p.strParams[prevParam-1] = true
+			}
+		case '+':
+			emit(opAdd, 0)
+		case '-':
+			emit(opSub, 0)
+		case '*':
+			emit(opMul, 0)
+		case '/':
+			emit(opDiv, 0)
+		case 'm':
+			emit(opMod, 0)
+		case '&':
+			emit(opAnd, 0)
+		case '|':
+			emit(opOr, 0)
+		case '^':
+			emit(opXor, 0)
+		case '=':
+			emit(opEq, 0)
+		case '<':
+			emit(opLt, 0)
+		case '>':
+			emit(opGt, 0)
+		case 'A':
+			emit(opLogicalAnd, 0)
+		case 'O':
+			emit(opLogicalOr, 0)
+		case '!':
+			emit(opNot, 0)
+		case '~':
+			emit(opComplement, 0)
+		case 'i':
+			emit(opIncr, 0)
+		case '?':
+			conds = append(conds, &conditional{pos: start, end: i + 1})
+			emit(opIf, 0)
+		case 't':
+			if len(conds) == 1 {
+				warn(ErrNotInConditional)
+			}
+			cond := conds[len(conds)-1]
+			cond.thens = append(cond.thens, len(p.insns))
+			emit(opJumpFalse, -1)
+		case 'e':
+			// a false %t goes to the %e or %; that ends its branch,
+			// and the end of a branch (a %e) to the %; that ends the
+			// whole conditional; whatever's nested in between is
+			// skipped along with the rest, as with ncurses.
+			if len(conds) == 1 {
+				warn(ErrNotInConditional)
+			}
+			cond := conds[len(conds)-1]
+			if len(cond.thens) == 0 {
+				warn(ErrMissingThen)
+			}
+			cond.elses = append(cond.elses, len(p.insns))
+			emit(opJump, -1)
+			cond.thens = resolve(cond.thens)
+		case ';':
+			if len(conds) == 1 {
+				warn(ErrNotInConditional)
+			}
+			cond := conds[len(conds)-1]
+			cond.thens = resolve(cond.thens)
+			cond.elses = resolve(cond.elses)
+			if len(conds) > 1 {
+				conds = conds[:len(conds)-1]
+			}
+			emit(opEndIf, 0)
+		default:
+			// as are operations it doesn't know, such as the %[ in
+			// xterm's u8
+			emit(opNop, 0)
+			warn(ErrBadParametrizedString)
+		}
+		for j := n; j < len(p.insns); j++ {
+			p.insns[j].pos, p.insns[j].end = start, i+1
+		}
+	}
+	// a missing %; is taken to be at the end
+	for j, cond := range conds {
+		resolve(cond.thens)
+		resolve(cond.elses)
+		if j > 0 && report != nil {
+			report(cond.pos, cond.end, ErrUnterminatedConditional)
+		}
+	}
+
+	return p, nil
+}
+
+// Execute runs the program with the given args, and returns its output.
+func (p *Program) Execute(args ...interface{}) ([]byte, error) {
+	return p.Append(nil, args...)
+}
+
+// Append runs the program with the given args, and appends its output
+// to buf. It doesn't allocate if buf has room for the output (and the
+// args are passed as a slice).
+//
+// Integer args, of any type, are numbers; strings, byte slices and
+// fmt.Stringers are strings.
+func (p *Program) Append(buf []byte, args ...interface{}) ([]byte, error) {
+	if p.statics != nil {
+		return p.exec(buf, p.statics, args, nil)
+	}
+	var statics variables
+	return p.exec(buf, &statics, args, nil)
+}
+
+// TParm runs the program the way ncurses' tparm does, and returns its
+// output. That is:
+//
+//   - params given to %s or %l are strings, and the others numbers;
+//     missing args are taken to be 0 (or ""), and strings given for
+//     numbers are parsed as tput does.
+//   - if there's no %p, the first two args (or the first one, or
+//     none, if fewer are popped before the program pushes values of
+//     its own) are pushed onto the stack, last first, before running it.
+//   - %i only adds 1 once, and with no %p, it also sets the bottom of
+//     the stack to the first two args plus 1 (or just 1, for those
+//     not pushed), in the order they were pushed, whatever has been
+//     popped since.
+//   - popping an empty stack gives a 0, popping the wrong type gives
+//     a 0 or "", and pushing onto a full one does nothing, rather than
+//     any of those being an error.
+//   - numbers are 32 bits.
+//
+// So its output is the same as tput's.
+func (p *Program) TParm(args ...interface{}) ([]byte, error) {
+	return p.AppendTParm(nil, args...)
+}
+
+// AppendTParm is like TParm, but appends the output to buf.
+func (p *Program) AppendTParm(buf []byte, args ...interface{}) ([]byte, error) {
+	statics := p.statics
+	if statics == nil {
+		statics = new(variables)
+	}
+	var params [9]value
+	for i := range params {
+		if p.strParams[i] {
+			params[i].isStr = true
+		}
+		if i >= len(args) {
+			continue
+		}
+		v, ok := argValue(args[i])
+		switch {
+		case !ok:
+			return nil, p.paramError(i+1, params[i].kind(), fmt.Sprintf("%T", args[i]))
+		case v.isStr == params[i].isStr:
+			params[i] = v
+		case v.isStr:
+			// as tput does with its command line
+			n, _ := strconv.ParseInt(v.str, 0, 64)
+			params[i].num = int(n)
+		default:
+			// ncurses fails, not having a string
+			return nil, p.paramError(i+1, "string", "number")
+		}
+	}
+	return p.exec(buf, statics, nil, &params)
+}
+
+// paramError returns an ErrTypeMismatch for an arg given for the nth
+// param, at its first %p.
+func (p *Program) paramError(n int, want, got string) error {
+	err := &ErrTypeMismatch{Op: fmt.Sprintf("%%p%d", n), Want: want, Got: got}
+	for i := range p.insns {
+		if in := &p.insns[i]; in.op == opParam && in.arg == n {
+			err.Op, err.Offset = p.op(in), in.pos
+			break
+		}
+	}
+	return err
+}
+
+// termcapParams returns how many params TParm pushes onto the stack for
+// a program with no %p. That's what ncurses' tparm reckons it pops of
+// them, going through it in order: it keeps track of how many values
+// have been pushed by the program itself (starting from -1), and counts
+// a param for each operation that pops while there are none, up to two.
+func (p *Program) termcapParams() int {
+	n, level := 0, -1
+	bump := func() {
+		if level < 0 && n < 2 {
+			n++
+		}
+	}
+	for _, in := range p.insns {
+		switch in.op {
+		case opPrintf:
+			bump()
+			// strings don't count, as ncurses has it
+			if in.arg != 's' {
+				level--
+			}
+		case opLen, opNot, opComplement:
+			bump()
+		case opConst, opGet:
+			level++
+		case opAdd, opSub, opMul, opDiv, opMod, opAnd, opOr, opXor,
+			opEq, opLt, opGt, opLogicalAnd, opLogicalOr:
+			bump()
+			level--
+		}
+	}
+	return n
+}
+
+// op returns the source of an instruction, for errors.
+func (p *Program) op(in *insn) string {
+	return string(p.src[in.pos:in.end])
+}
+
+// exec runs the program with either args, or, for TParm, params.
+func (p *Program) exec(buf []byte, statics *variables, args []interface{}, params *[9]value) ([]byte, error) {
+	stack := paramStack{p: p, tparm: params != nil}
+	if params != nil && !p.hasParams {
+		// the args that aren't pushed are 0, as far as %i goes
+		n := p.termcapParams()
+		for i := n; i < len(params); i++ {
+			params[i] = value{isStr: params[i].isStr}
+		}
+		for i := n - 1; i >= 0; i-- {
+			stack.push(params[i])
+		}
+	}
+	var dynamics variables
+	// %i adds 1 to the first two parameters
+	incr := 0
+	for pc := 0; pc < len(p.insns) && stack.err == nil; pc++ {
+		in := &p.insns[pc]
+		stack.in = in
+		switch in.op {
+		case opText:
+			buf = append(buf, in.text...)
+		case opPrintf:
+			v := stack.popKind(in.arg == 's')
+			switch {
+			case stack.err != nil:
+			case in.arg == 's':
+				buf = in.f.appendString(buf, v.str)
+			case in.arg == 'c':
+				buf = appendChar(buf, v.num)
+			default:
+				buf = in.f.appendInt(buf, byte(in.arg), v.num)
+			}
+		case opParam:
+			var v value
+			switch {
+			case params != nil:
+				v = params[in.arg-1]
+			case in.arg > len(args):
+				return nil, ErrMissingArgs
+			default:
+				var ok bool
+				v, ok = argValue(args[in.arg-1])
+				if !ok {
+					return nil, &ErrTypeMismatch{Offset: in.pos, Op: p.op(in), Want: "number or string", Got: fmt.Sprintf("%T", args[in.arg-1])}
+				}
+			}
+			if !v.isStr && in.arg <= 2 {
+				v.num += incr
+			}
+			stack.push(v)
+		case opConst:
+			stack.pushInt(in.arg)
+		case opSet, opGet:
+			vars, n := &dynamics, in.arg
+			if n >= len(dynamics) {
+				vars, n = statics, n-len(dynamics)
+			}
+			if in.op == opSet {
+				vars[n] = stack.pop()
+			} else {
+				stack.push(vars[n])
+			}
+		case opLen:
+			stack.pushInt(len(stack.popString()))
+		case opAdd:
+			stack.pushInt(stack.popInt() + stack.popInt())
+		case opSub:
+			stack.pushInt(-stack.popInt() + stack.popInt())
+		case opMul:
+			stack.pushInt(stack.popInt() * stack.popInt())
+		case opDiv, opMod:
+			n1 := stack.popInt()
+			n2 := stack.popInt()
+			switch {
+			case n1 == 0:
+				// as ncurses does
+				stack.pushInt(0)
+			case in.op == opDiv:
+				stack.pushInt(n2 / n1)
+			default:
+				stack.pushInt(n2 % n1)
+			}
+		case opAnd:
+			stack.pushInt(stack.popInt() & stack.popInt())
+		case opOr:
+			stack.pushInt(stack.popInt() | stack.popInt())
+		case opXor:
+			stack.pushInt(stack.popInt() ^ stack.popInt())
+		case opEq:
+			stack.pushBool(stack.popInt() == stack.popInt())
+		case opLt:
+			stack.pushBool(stack.popInt() > stack.popInt())
+		case opGt:
+			stack.pushBool(stack.popInt() < stack.popInt())
+		case opLogicalAnd:
+			b1 := stack.popBool()
+			b2 := stack.popBool()
+			stack.pushBool(b1 && b2)
+		case opLogicalOr:
+			b1 := stack.popBool()
+			b2 := stack.popBool()
+			stack.pushBool(b1 || b2)
+		case opNot:
+			stack.pushBool(!stack.popBool())
+		case opComplement:
+			// TODO: find an example of this to check word size & etc
+			stack.pushInt(^stack.popInt())
+		case opIncr:
+			if params == nil {
+				incr++
+				break
+			}
+			// tparm only does it once, and with no %p, it also puts
+			// the first two params, plus 1, at the bottom of the
+			// stack, where it pushed them (whether they're still
+			// there or not)
+			if incr == 0 && !p.hasParams {
+				for i := 0; i < 2; i++ {
+					if !params[i].isStr {
+						stack.vals[i] = value{num: params[i].num + 1}
+					}
+				}
+			}
+			incr = 1
+		case opJumpFalse:
+			if !stack.popBool() {
+				pc = in.arg - 1
+			}
+		case opJump:
+			pc = in.arg - 1
+		}
+	}
+	if stack.err != nil {
+		return nil, stack.err
+	}
+	return buf, nil
+}
@@ -0,0 +1,417 @@
+package terminfo_test
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"gopkg.in/check.v1"
+
+	"gopkg.in/terminfo.v0"
+)
+
+func (*tiSuite) TestCompile(c *check.C) {
+	p, err := terminfo.Compile([]byte("\x1b[%i%p1%d;%p2%dH"))
+	c.Assert(err, check.IsNil)
+	for _, s := range []struct {
+		x, y int
+		res  string
+	}{
+		{0, 0, "\x1b[1;1H"},
+		{23, 79, "\x1b[24;80H"},
+		{9, 1000, "\x1b[10;1001H"},
+	} {
+		buf, err := p.Execute(s.x, s.y)
+		c.Assert(err, check.IsNil)
+		c.Check(string(buf), check.Equals, s.res)
+	}
+
+	buf, err := p.Append([]byte("foo"), 1, 2)
+	c.Assert(err, check.IsNil)
+	c.Check(string(buf), check.Equals, "foo\x1b[2;3H")
+
+	_, err = p.Execute(1)
+	c.Check(err, check.Equals, terminfo.ErrMissingArgs)
+}
+
+func (*tiSuite) TestCompileErrors(c *check.C) {
+	for _, s := range []struct {
+		tpl string
+		err error
+	}{
+		{"%p", terminfo.ErrTruncatedParametrizedString},
+		{"%p0", terminfo.ErrBadParametrizedString},
+		{"%px", terminfo.ErrBadParametrizedString},
+		{"%{12", terminfo.ErrTruncatedParametrizedString},
+		{"%{1a}", terminfo.ErrBadParametrizedString},
+		{"%'a", terminfo.ErrTruncatedParametrizedString},
+		{"%'ab", terminfo.ErrBadParametrizedString},
+		{"%p1%2.2", terminfo.ErrTruncatedParametrizedString},
+		{"%p1%2.2.2d", terminfo.ErrBadParametrizedString},
+		{"%p1%99999d", terminfo.ErrBadParametrizedString},
+	} {
+		p, err := terminfo.Compile([]byte(s.tpl))
+		c.Check(p, check.IsNil, check.Commentf(s.tpl))
+		c.Check(err, check.Equals, s.err, check.Commentf(s.tpl))
+	}
+}
+
+func (*tiSuite) TestFormat(c *check.C) {
+	// what ncurses' tparm makes of these
+	for _, s := range []struct {
+		tpl string
+		arg interface{}
+		res string
+	}{
+		{"%p1%d", -42, "-42"},
+		{"%p1%5d|", 42, "   42|"},
+		{"%p1%:-5d|", 42, "42   |"},
+		{"%p1%::-4d|", 5, "5   |"},
+		{"%p1%:4d|", 5, "   5|"},
+		{"%p1%05d", 42, "00042"},
+		{"%p1%:-05d|", 42, "42   |"},
+		{"%p1% d", 42, " 42"},
+		{"%p1% 05d", 42, " 0042"},
+		{"%p1%  d", -5, "-5"},
+		{"%p1%.3d", 42, "042"},
+		{"%p1%8.3d", -42, "    -042"},
+		{"%p1%:-8.3d|", -42, "-042    |"},
+		{"%p1%08.4d", -7, "   -0007"},
+		{"%p1%.0d", 0, ""},
+		{"%p1%5.d", 5, "    5"},
+		{"%p1%#d", 5, "5"},
+		{"%p1%x", 255, "ff"},
+		{"%p1%X", 255, "FF"},
+		{"%p1%#x", 42, "0x2a"},
+		{"%p1%#X", 42, "0X2A"},
+		{"%p1%#x", 0, "0"},
+		{"%p1%#.0x", 0, ""},
+		{"%p1%#8x", 255, "    0xff"},
+		{"%p1%:-#8X|", 255, "0XFF    |"},
+		{"%p1%05x", 42, "0002a"},
+		{"%p1% x", 5, "5"},
+		{"%p1%x", -1, "ffffffff"},
+		{"%p1%X", -255, "FFFFFF01"},
+		{"%p1%o", 8, "10"},
+		{"%p1%o", -1, "37777777777"},
+		{"%p1%#o", 42, "052"},
+		{"%p1%#o", 0, "0"},
+		{"%p1%#.0o", 0, "0"},
+		{"%p1%#5.3o", 8, "  010"},
+		{"%p1%c", 65, "A"},
+		{"%p1%c", 0, "\x80"},
+		{"%p1%c", 200, "\xc8"},
+		{"%p1%c", -1, "\xff"},
+		{"%p1%5c", 65, "A"},
+		{"%p1%:-3c", 65, "A"},
+		{"%p1%s", "abc", "abc"},
+		{"%p1%5s|", "abc", "  abc|"},
+		{"%p1%:-5s|", "abc", "abc  |"},
+		{"%p1%.2s", "abcd", "ab"},
+		{"%p1%5.2s|", "abcd", "   ab|"},
+		{"%p1%.0s|", "abcd", "|"},
+		{"%p1%05s", "ab", "   ab"},
+		{"%p1%# s", "ab", "ab"},
+		// a format before something else is ignored, and without the
+		// ':' a '-' is a subtraction
+		{"%p1%5%", 1, "%"},
+		{"%p1%{2}%-5d", 9, "5d"},
+		{"%p1%p1%:+%d", 1, "2"},
+	} {
+		buf, err := terminfo.UnescapeString(s.tpl, s.arg)
+		c.Assert(err, check.IsNil, check.Commentf("%q", s.tpl))
+		c.Check(string(buf), check.Equals, s.res, check.Commentf("%q", s.tpl))
+	}
+}
+
+func (*tiSuite) TestProgramAllocs(c *check.C) {
+	p, err := terminfo.Compile([]byte("\x1b[%i%p1%d;%p2%dH\x1b[%?%p3%{8}%<%t3%p3%d%e38;5;%p3%d%;m"))
+	c.Assert(err, check.IsNil)
+	buf := make([]byte, 0, 64)
+	args := []interface{}{100, 200, 1000}
+	allocs := testing.AllocsPerRun(100, func() {
+		buf, err = p.Append(buf[:0], args...)
+	})
+	c.Assert(err, check.IsNil)
+	c.Check(string(buf), check.Equals, "\x1b[101;201H\x1b[38;5;1000m")
+	c.Check(allocs, check.Equals, 0.0)
+}
+
+func (*tiSuite) TestProgramCache(c *check.C) {
+	ti := &terminfo.TermInfo{Strings: map[terminfo.StringIndex][]byte{
+		terminfo.User0: []byte("%p1%PA"),
+		terminfo.User1: []byte("%gA%d"),
+	}}
+	p0, err := ti.Program(terminfo.User0)
+	c.Assert(err, check.IsNil)
+	p1, err := ti.Program(terminfo.User1)
+	c.Assert(err, check.IsNil)
+	p, err := ti.Program(terminfo.User0)
+	c.Assert(err, check.IsNil)
+	c.Check(p, check.Equals, p0)
+
+	// programs from a TermInfo share its statics
+	_, err = p0.Execute(42)
+	c.Assert(err, check.IsNil)
+	buf, err := p1.Execute()
+	c.Assert(err, check.IsNil)
+	c.Check(string(buf), check.Equals, "42")
+	buf, err = ti.Unescape(terminfo.User1)
+	c.Assert(err, check.IsNil)
+	c.Check(string(buf), check.Equals, "42")
+
+	// changing the string gets it compiled again
+	ti.Strings[terminfo.User1] = []byte("%gA%x")
+	buf, err = ti.Unescape(terminfo.User1)
+	c.Assert(err, check.IsNil)
+	c.Check(string(buf), check.Equals, "2a")
+
+	// absent strings are empty programs
+	buf, err = ti.Unescape(terminfo.User9)
+	c.Assert(err, check.IsNil)
+	c.Check(buf, check.HasLen, 0)
+
+	ti.Strings[terminfo.User2] = []byte("%{1")
+	_, err = ti.Program(terminfo.User2)
+	c.Check(err, check.Equals, terminfo.ErrTruncatedParametrizedString)
+}
+
+func (*tiSuite) TestStackErrors(c *check.C) {
+	for _, s := range []struct {
+		tpl  string
+		args []interface{}
+		err  error
+	}{
+		{"%d", nil, &terminfo.ErrStackUnderflow{Op: "%d", Offset: 0}},
+		{"ab%p1%+%d", []interface{}{1}, &terminfo.ErrStackUnderflow{Op: "%+", Offset: 5}},
+		{"%?%t%;", nil, &terminfo.ErrStackUnderflow{Op: "%t", Offset: 2}},
+		{"%Pa", nil, &terminfo.ErrStackUnderflow{Op: "%Pa", Offset: 0}},
+		{strings.Repeat("%{1}", 20) + "%p1", []interface{}{1}, &terminfo.ErrBadOp{Op: "%p1", Offset: 80, Err: terminfo.ErrStackOverflow}},
+		{"\x1b[%p1%d", []interface{}{"x"}, &terminfo.ErrTypeMismatch{Op: "%d", Offset: 5, Want: "number", Got: "string"}},
+		{"%p1%2.2X", []interface{}{"x"}, &terminfo.ErrTypeMismatch{Op: "%2.2X", Offset: 3, Want: "number", Got: "string"}},
+		{"%p1%s", []interface{}{1}, &terminfo.ErrTypeMismatch{Op: "%s", Offset: 3, Want: "string", Got: "number"}},
+		{"%p1%l", []interface{}{1}, &terminfo.ErrTypeMismatch{Op: "%l", Offset: 3, Want: "string", Got: "number"}},
+		{"%p1%{1}%+", []interface{}{"x"}, &terminfo.ErrTypeMismatch{Op: "%+", Offset: 7, Want: "number", Got: "string"}},
+		{"%p1%d", []interface{}{1.5}, &terminfo.ErrTypeMismatch{Op: "%p1", Offset: 0, Want: "number or string", Got: "float64"}},
+	} {
+		_, err := terminfo.UnescapeString(s.tpl, s.args...)
+		c.Check(err, check.DeepEquals, s.err, check.Commentf("%q", s.tpl))
+	}
+
+	c.Check(terminfo.ErrStackUnderflow{Op: "%+", Offset: 5}, check.ErrorMatches,
+		`stack underflow in parametrized string: %\+ at offset 5`)
+	c.Check(terminfo.ErrTypeMismatch{Op: "%d", Offset: 5, Want: "number", Got: "string"}, check.ErrorMatches,
+		`type mismatch in parametrized string: %d at offset 5 wants a number, got a string`)
+
+	ti := &terminfo.TermInfo{Strings: map[terminfo.StringIndex][]byte{
+		terminfo.User0: []byte("%p1%p2%+%d"),
+	}}
+	_, err := ti.Unescape(terminfo.User0, 1)
+	c.Check(err, check.Equals, terminfo.ErrMissingArgs)
+	_, err = ti.Unescape(terminfo.User0, 1, "x")
+	c.Check(err, check.FitsTypeOf, &terminfo.ErrTypeMismatch{})
+	c.Check(func() { ti.MustUnescape(terminfo.User0, 1, "x") }, check.PanicMatches, "type mismatch .*")
+	c.Check(ti.MustUnescape(terminfo.User0, 1, 2), check.Equals, "3")
+}
+
+// a number, even if it has a String method
+type shade uint8
+
+func (shade) String() string { return "not me" }
+
+type name []byte
+
+type label struct{ s string }
+
+func (l label) String() string { return l.s }
+
+func (*tiSuite) TestArgTypes(c *check.C) {
+	p, err := terminfo.Compile([]byte("%p1%d %p2%s"))
+	c.Assert(err, check.IsNil)
+	for _, s := range []struct {
+		num, str interface{}
+		res      string
+	}{
+		{42, "x", "42 x"},
+		{int8(-42), []byte("x"), "-42 x"},
+		{int16(42), name("x"), "42 x"},
+		{int32(42), label{"x"}, "42 x"},
+		{int64(42), "x", "42 x"},
+		{uint(42), "x", "42 x"},
+		{uint8(42), "x", "42 x"},
+		{uint16(42), "x", "42 x"},
+		{uint32(42), "x", "42 x"},
+		{uint64(42), "x", "42 x"},
+		{uintptr(42), "x", "42 x"},
+		{shade(42), "x", "42 x"},
+	} {
+		buf, err := p.Execute(s.num, s.str)
+		c.Assert(err, check.IsNil, check.Commentf("%T, %T", s.num, s.str))
+		c.Check(string(buf), check.Equals, s.res, check.Commentf("%T, %T", s.num, s.str))
+	}
+
+	// %i doesn't change the args
+	args := []interface{}{1, 2, uint8(3)}
+	buf, err := terminfo.UnescapeString("%i%p1%d;%p2%d;%p3%d", args...)
+	c.Assert(err, check.IsNil)
+	c.Check(string(buf), check.Equals, "2;3;3")
+	c.Check(args, check.DeepEquals, []interface{}{1, 2, uint8(3)})
+
+	ti := &terminfo.TermInfo{
+		Numbers: []int16{terminfo.MaxColors: 256},
+		Strings: map[terminfo.StringIndex][]byte{
+			terminfo.User0: []byte("%p1%d"),
+		},
+	}
+	c.Check(ti.MustUnescape(terminfo.User0, ti.Numbers[terminfo.MaxColors]), check.Equals, "256")
+}
+
+func (*tiSuite) TestDivisionByZero(c *check.C) {
+	for _, op := range []string{"/", "m"} {
+		buf, err := terminfo.UnescapeString("%p1%{0}%"+op+"%d", 12)
+		c.Assert(err, check.IsNil, check.Commentf(op))
+		c.Check(string(buf), check.Equals, "0", check.Commentf(op))
+	}
+}
+
+func (*tiSuite) TestNestedConditionals(c *check.C) {
+	p, err := terminfo.Compile([]byte("<%?%p1%t%?%p2%tA%eB%;%eC%;>"))
+	c.Assert(err, check.IsNil)
+	for _, s := range []struct {
+		p1, p2 int
+		res    string
+	}{
+		{1, 1, "<A>"},
+		{1, 0, "<B>"},
+		{0, 1, "<C>"},
+		{0, 0, "<C>"},
+	} {
+		buf, err := p.Execute(s.p1, s.p2)
+		c.Assert(err, check.IsNil)
+		c.Check(string(buf), check.Equals, s.res, check.Commentf("%d, %d", s.p1, s.p2))
+	}
+
+	// else-if chains, nested in the else of another
+	p, err = terminfo.Compile([]byte("%?%p1%{1}%=%tone%e%?%p1%{2}%=%ttwo%e%p1%{3}%=%tthree%eother%;%;."))
+	c.Assert(err, check.IsNil)
+	for i, res := range []string{"other.", "one.", "two.", "three.", "other."} {
+		buf, err := p.Execute(i)
+		c.Assert(err, check.IsNil)
+		c.Check(string(buf), check.Equals, res)
+	}
+}
+
+// testdata/tparm.txt has what ncurses' tparm makes of the sgr, setaf and
+// setab of some real entries; see testdata/gen-tparm.py.
+func (*tiSuite) TestTParmConformance(c *check.C) {
+	f, err := os.Open("testdata/tparm.txt")
+	c.Assert(err, check.IsNil)
+	defer f.Close()
+	n := 0
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		c.Assert(fields, check.HasLen, 5, check.Commentf(line))
+		var args []interface{}
+		for _, p := range strings.Split(fields[2], ",") {
+			n, err := strconv.Atoi(p)
+			c.Assert(err, check.IsNil)
+			args = append(args, n)
+		}
+		tpl, err := strconv.Unquote(fields[3])
+		c.Assert(err, check.IsNil)
+		out, err := strconv.Unquote(fields[4])
+		c.Assert(err, check.IsNil)
+
+		buf, err := terminfo.UnescapeString(tpl, args...)
+		comment := check.Commentf("%s %s %s", fields[0], fields[1], fields[2])
+		c.Check(err, check.IsNil, comment)
+		c.Check(string(buf), check.Equals, out, comment)
+		buf, err = terminfo.TParmString(tpl, args...)
+		c.Check(err, check.IsNil, comment)
+		c.Check(string(buf), check.Equals, out, comment)
+		n++
+	}
+	c.Assert(scanner.Err(), check.IsNil)
+	c.Check(n > 1000, check.Equals, true)
+}
+
+func (*tiSuite) TestTParm(c *check.C) {
+	// what tput makes of these
+	for _, s := range []struct {
+		tpl  string
+		args []interface{}
+		res  string
+	}{
+		{"%p1%d;%p2%d", []interface{}{5}, "5;0"},
+		{"%p9%d", []interface{}{1}, "0"},
+		{"%i%p1%d;%p2%d;%p3%d", []interface{}{1, 2, 3}, "2;3;3"},
+		{"%p1%d", []interface{}{"0x10"}, "16"},
+		{"%p1%d", []interface{}{"junk"}, "0"},
+		// what it doesn't know is left out
+		{"a%zb", nil, "ab"},
+		{"%p1%Q%d", []interface{}{5}, "5"},
+		{"abc%", nil, "abc"},
+		// numbers are C ints
+		{"%{2147483647}%{1}%+%x", nil, "80000000"},
+		{"%p1%{2}%*%d", []interface{}{2000000000}, "-294967296"},
+		// no %p's, so the args are pushed
+		{"%c%c", []interface{}{65, 66}, "AB"},
+		{"%d;%s", []interface{}{1}, "1;"},
+		// but no more than two of them, or than are popped
+		{"%d;%d;%d", []interface{}{10, 20, 30}, "10;20;0"},
+		{"%+%+%d", []interface{}{1, 2, 4}, "3"},
+		// less those the program pushes itself before popping them
+		{"%{1}%Pa%d%d", []interface{}{7, 8}, "70"},
+		{"%{1}%{2}%Pa%Pb%d%d", []interface{}{7, 8}, "00"},
+		{"%gb%Pb%.3d", []interface{}{7, 8}, "000"},
+		{"%{1}%+%d%d", []interface{}{10, 20}, "1120"},
+		// and %i adds 1 to them where they were pushed, as with u6
+		{"\x1b[%i%d;%dR", []interface{}{1, 2}, "\x1b[3;2R"},
+		{"%i%d;%d;%d", []interface{}{10, 20, 30}, "21;11;0"},
+		{"%d%i;%d;%d", []interface{}{10, 20, 30}, "10;11;0"},
+		{"%d;%d;%i%d", []interface{}{10, 20, 30}, "10;20;0"},
+		{"%i%d", []interface{}{1, 2}, "2"},
+		{"%i%i%d;%d", []interface{}{1, 2}, "3;2"},
+		{"%{3}%i%+%d", []interface{}{10, 20}, "12"},
+		// %i only adds 1 once
+		{"%i%i%p1%d", []interface{}{1}, "2"},
+		{"%p1%d%i%p1%d", []interface{}{1}, "12"},
+		// nor errors for the stack
+		{"%p1%d%d|", []interface{}{1}, "10|"},
+		{"%p1%d%p1%d%+%d", []interface{}{1}, "110"},
+		{"%{5}%l%d", nil, "0"},
+		{"%{5}%s|", nil, "|"},
+		{strings.Repeat("%{1}", 20) + "%{2}%d", nil, "1"},
+		// params for %s and %l are strings
+		{"%p1%d%p2%l%d", []interface{}{1, "hello"}, "15"},
+		{"%p1%d%p2%l%d", []interface{}{1}, "10"},
+		{"%p1%d%p2%s", []interface{}{1}, "1"},
+		{"%p2%s%p2%d", []interface{}{1, "x"}, "x0"},
+		{"%i%p1%d%p2%s", []interface{}{1, "x"}, "2x"},
+	} {
+		buf, err := terminfo.TParmString(s.tpl, s.args...)
+		c.Assert(err, check.IsNil, check.Commentf("%q", s.tpl))
+		c.Check(string(buf), check.Equals, s.res, check.Commentf("%q", s.tpl))
+	}
+
+	// ncurses won't take a number for a string
+	_, err := terminfo.TParmString("ab%p1%l%d", 1)
+	c.Check(err, check.DeepEquals, &terminfo.ErrTypeMismatch{Op: "%p1", Offset: 2, Want: "string", Got: "number"})
+	_, err = terminfo.TParmString("%p1%d", 1.5)
+	c.Check(err, check.DeepEquals, &terminfo.ErrTypeMismatch{Op: "%p1", Offset: 0, Want: "number", Got: "float64"})
+
+	ti := &terminfo.TermInfo{Strings: map[terminfo.StringIndex][]byte{
+		terminfo.User0: []byte("%p1%d;%p2%d"),
+	}}
+	_, err = ti.Unescape(terminfo.User0, 1)
+	c.Check(err, check.Equals, terminfo.ErrMissingArgs)
+	ti.TParm = true
+	c.Check(ti.MustUnescape(terminfo.User0, 1), check.Equals, "1;0")
+}
@@ -0,0 +1,167 @@
+package terminfo
+
+import (
+	"io"
+	"regexp"
+	"strconv"
+	"time"
+
+	"gopkg.in/termios.v0"
+)
+
+var findPadIndexes = regexp.MustCompile(`\$<(\d+)(\*)?(/)?>`).FindAllSubmatchIndex
+
+// An OutputSpeeder knows the speed, in baud, of the line it writes to.
+// Fputs uses it to work out how many pad characters to send.
+type OutputSpeeder interface {
+	OutputSpeed() int
+}
+
+// Puts evaluates the string capability idx with the given args, and
+// writes it to the TermInfo's tty, padded for affcnt lines affected.
+// Padding is sent as pad characters when the speed of the tty allows,
+// or else waited for.
+func (ti *TermInfo) Puts(idx StringIndex, affcnt int, args ...interface{}) error {
+	buf, err := ti.Unescape(idx, args...)
+	if err != nil {
+		return err
+	}
+	ti.ttySpeedOnce.Do(func() {
+		if tio, err := termios.GetAttr(ti.tty.Fd()); err == nil {
+			_, ti.ttySpeed = tio.GetSpeed()
+		}
+	})
+	_, err = ti.puts(ti.tty, ti.ttySpeed, buf, affcnt, true)
+	return err
+}
+
+// Fputs is like Puts, but writes to w, whose speed is taken from its
+// OutputSpeed method if it has one. It doesn't wait: padding that
+// can't be sent as pad characters is left out, and what it adds up to
+// returned, for the caller to wait for after writing. That's right for
+// padding at the end of a string, which is where it usually is; see
+// Segments for padding elsewhere.
+func (ti *TermInfo) Fputs(w io.Writer, idx StringIndex, affcnt int, args ...interface{}) (time.Duration, error) {
+	buf, err := ti.Unescape(idx, args...)
+	if err != nil {
+		return 0, err
+	}
+	speed := 0
+	if w, ok := w.(OutputSpeeder); ok {
+		speed = w.OutputSpeed()
+	}
+	return ti.puts(w, speed, buf, affcnt, false)
+}
+
+// A Segment is a part of an evaluated string capability: text, and the
+// padding that comes after it.
+type Segment struct {
+	Text []byte
+	// Delay is how long the padding is, in milliseconds, already
+	// multiplied by the number of lines affected if it's Proportional
+	// (a $<n*>). Mandatory padding (a $<n/>) is needed even on
+	// terminals with flow control.
+	Delay        int
+	Mandatory    bool
+	Proportional bool
+}
+
+// Segments evaluates the string capability idx with the given args, and
+// splits it where it's padded, for affcnt lines affected, so that the
+// caller can send it and wait as it sees fit (or not at all). Text with
+// no padding after it is in a last Segment with no Delay.
+func (ti *TermInfo) Segments(idx StringIndex, affcnt int, args ...interface{}) ([]Segment, error) {
+	buf, err := ti.Unescape(idx, args...)
+	if err != nil {
+		return nil, err
+	}
+	return segments(buf, affcnt)
+}
+
+func segments(buf []byte, affcnt int) ([]Segment, error) {
+	var segs []Segment
+	o := 0
+	for _, idx := range findPadIndexes(buf, -1) {
+		n, err := strconv.Atoi(string(buf[idx[2]:idx[3]]))
+		if err != nil {
+			return nil, err
+		}
+		seg := Segment{
+			Text:         buf[o:idx[0]:idx[0]],
+			Delay:        n,
+			Mandatory:    idx[6] != -1,
+			Proportional: idx[4] != -1,
+		}
+		if seg.Proportional {
+			seg.Delay *= affcnt
+		}
+		segs = append(segs, seg)
+		o = idx[1]
+	}
+	if o < len(buf) {
+		segs = append(segs, Segment{Text: buf[o:]})
+	}
+	return segs, nil
+}
+
+// puts writes buf to w, padded for affcnt lines at speed baud (0 if
+// unknown), in a single write unless it sleeps for the padding that
+// takes a delay.
+func (ti *TermInfo) puts(w io.Writer, speed int, buf []byte, affcnt int, sleep bool) (time.Duration, error) {
+	segs, err := segments(buf, affcnt)
+	if err != nil {
+		return 0, err
+	}
+	out := make([]byte, 0, len(buf))
+	var delay time.Duration
+	for _, seg := range segs {
+		out = append(out, seg.Text...)
+		if seg.Delay == 0 {
+			continue
+		}
+		pad, d := ti.padding(seg.Delay, seg.Mandatory, speed)
+		out = append(out, pad...)
+		if d > 0 && sleep {
+			if _, err := w.Write(out); err != nil {
+				return delay, err
+			}
+			out = out[:0]
+			time.Sleep(d)
+		} else {
+			delay += d
+		}
+	}
+	_, err = w.Write(out)
+	return delay, err
+}
+
+// padding works out how to pad for n milliseconds (if mandatory, or if
+// the terminal needs it at speed baud), and returns either the pad
+// characters to send or how long to wait instead.
+func (ti *TermInfo) padding(n int, mandatory bool, speed int) ([]byte, time.Duration) {
+	// NOTE this seems to be right, but also seems to produce very
+	// different results from what `tput` does.
+	if !mandatory {
+		if ti.flag(XonXoff) {
+			return nil, 0
+		}
+		if minBaudRate := ti.number(PaddingBaudRate); minBaudRate < 0 || speed < minBaudRate {
+			return nil, 0
+		}
+	}
+
+	delay := time.Duration(n) * time.Millisecond
+	if ti.flag(NoPadChar) || ti.flag(XonXoff) || speed <= 0 {
+		return nil, delay
+	}
+	padSeq := ti.Strings[PadChar]
+	if len(padSeq) == 0 {
+		padSeq = []byte{0}
+	}
+	numPad := n * speed / 9000 / len(padSeq)
+	var pad []byte
+	for i := 0; i < numPad; i++ {
+		pad = append(pad, padSeq...)
+	}
+	return pad, 0
+}
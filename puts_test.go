@@ -0,0 +1,137 @@
+package terminfo_test
+
+import (
+	"bytes"
+	"time"
+
+	"gopkg.in/check.v1"
+
+	"gopkg.in/terminfo.v0"
+)
+
+// line is a writer to a line of the given speed, that counts writes.
+type line struct {
+	bytes.Buffer
+	speed  int
+	writes int
+}
+
+func (l *line) Write(p []byte) (int, error) {
+	l.writes++
+	return l.Buffer.Write(p)
+}
+
+func (l *line) OutputSpeed() int { return l.speed }
+
+func (*tiSuite) TestFputs(c *check.C) {
+	ti := &terminfo.TermInfo{
+		Booleans: make([]bool, terminfo.NoPadChar+1),
+		Numbers:  make([]int16, terminfo.PaddingBaudRate+1),
+		Strings: map[terminfo.StringIndex][]byte{
+			terminfo.ClearScreen:    []byte("\x1b[H\x1b[2J$<50>"),
+			terminfo.DeleteLine:     []byte("\x1b[M$<5*>"),
+			terminfo.FlashScreen:    []byte("\x1b[?5h$<100/>\x1b[?5l"),
+			terminfo.ParmDownCursor: []byte("\x1b[%p1%dB$<2>"),
+		},
+	}
+	ti.Numbers[terminfo.PaddingBaudRate] = 9600
+
+	for _, s := range []struct {
+		idx    terminfo.StringIndex
+		affcnt int
+		speed  int
+		out    string
+		delay  time.Duration
+	}{
+		// 50ms at 9600 baud is 53 nulls
+		{terminfo.ClearScreen, 1, 9600, "\x1b[H\x1b[2J" + string(make([]byte, 53)), 0},
+		// too slow to need padding
+		{terminfo.ClearScreen, 1, 2400, "\x1b[H\x1b[2J", 0},
+		// but mandatory padding is always there, as a delay if the
+		// speed isn't known
+		{terminfo.FlashScreen, 1, 2400, "\x1b[?5h" + string(make([]byte, 26)) + "\x1b[?5l", 0},
+		{terminfo.FlashScreen, 1, 0, "\x1b[?5h\x1b[?5l", 100 * time.Millisecond},
+		{terminfo.DeleteLine, 10, 19200, "\x1b[M" + string(make([]byte, 106)), 0},
+	} {
+		w := &line{speed: s.speed}
+		delay, err := ti.Fputs(w, s.idx, s.affcnt)
+		c.Assert(err, check.IsNil)
+		c.Check(w.String(), check.Equals, s.out, check.Commentf("%v at %d", s.idx, s.speed))
+		c.Check(delay, check.Equals, s.delay, check.Commentf("%v at %d", s.idx, s.speed))
+		c.Check(w.writes, check.Equals, 1)
+	}
+
+	// args, and plain writers, whose speed isn't known
+	var buf bytes.Buffer
+	delay, err := ti.Fputs(&buf, terminfo.ParmDownCursor, 1, 3)
+	c.Assert(err, check.IsNil)
+	c.Check(buf.String(), check.Equals, "\x1b[3B")
+	c.Check(delay, check.Equals, time.Duration(0))
+
+	// other pad characters
+	ti.Strings[terminfo.PadChar] = []byte("\x7f")
+	w := &line{speed: 9600}
+	_, err = ti.Fputs(w, terminfo.ClearScreen, 1)
+	c.Assert(err, check.IsNil)
+	c.Check(w.String(), check.Equals, "\x1b[H\x1b[2J"+string(bytes.Repeat([]byte{0x7f}, 53)))
+
+	// terminals that can't be padded with them get delays
+	ti.Booleans[terminfo.NoPadChar] = true
+	w = &line{speed: 9600}
+	delay, err = ti.Fputs(w, terminfo.ClearScreen, 1)
+	c.Assert(err, check.IsNil)
+	c.Check(w.String(), check.Equals, "\x1b[H\x1b[2J")
+	c.Check(delay, check.Equals, 50*time.Millisecond)
+
+	// and those with flow control don't need padding
+	ti.Booleans[terminfo.XonXoff] = true
+	w = &line{speed: 9600}
+	delay, err = ti.Fputs(w, terminfo.ClearScreen, 1)
+	c.Assert(err, check.IsNil)
+	c.Check(w.String(), check.Equals, "\x1b[H\x1b[2J")
+	c.Check(delay, check.Equals, time.Duration(0))
+
+	_, err = ti.Fputs(w, terminfo.ParmDownCursor, 1)
+	c.Check(err, check.Equals, terminfo.ErrMissingArgs)
+}
+
+func (*tiSuite) TestSegments(c *check.C) {
+	ti := &terminfo.TermInfo{Strings: map[terminfo.StringIndex][]byte{
+		terminfo.ClearScreen:    []byte("\x1b[H\x1b[2J$<50>"),
+		terminfo.DeleteLine:     []byte("\x1b[M$<5*>"),
+		terminfo.FlashScreen:    []byte("\x1b[?5h$<100/>\x1b[?5l"),
+		terminfo.ParmDownCursor: []byte("\x1b[%p1%dB"),
+		terminfo.User0:          []byte("$<1*/>$<2>x"),
+	}}
+	for _, s := range []struct {
+		idx  terminfo.StringIndex
+		args []interface{}
+		segs []terminfo.Segment
+	}{
+		{terminfo.ClearScreen, nil, []terminfo.Segment{
+			{Text: []byte("\x1b[H\x1b[2J"), Delay: 50},
+		}},
+		{terminfo.DeleteLine, nil, []terminfo.Segment{
+			{Text: []byte("\x1b[M"), Delay: 15, Proportional: true},
+		}},
+		{terminfo.FlashScreen, nil, []terminfo.Segment{
+			{Text: []byte("\x1b[?5h"), Delay: 100, Mandatory: true},
+			{Text: []byte("\x1b[?5l")},
+		}},
+		{terminfo.ParmDownCursor, []interface{}{4}, []terminfo.Segment{
+			{Text: []byte("\x1b[4B")},
+		}},
+		{terminfo.User0, nil, []terminfo.Segment{
+			{Text: []byte{}, Delay: 3, Mandatory: true, Proportional: true},
+			{Text: []byte{}, Delay: 2},
+			{Text: []byte("x")},
+		}},
+		{terminfo.User1, nil, nil},
+	} {
+		segs, err := ti.Segments(s.idx, 3, s.args...)
+		c.Assert(err, check.IsNil)
+		c.Check(segs, check.DeepEquals, s.segs, check.Commentf("%v", s.idx))
+	}
+	_, err := ti.Segments(terminfo.ParmDownCursor, 1)
+	c.Check(err, check.Equals, terminfo.ErrMissingArgs)
+}
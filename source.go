@@ -0,0 +1,523 @@
+package terminfo
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// capKind is the type of a capability in terminfo source.
+type capKind byte
+
+const (
+	boolCap capKind = 'b'
+	numCap  capKind = 'n'
+	strCap  capKind = 's'
+)
+
+func (k capKind) String() string {
+	switch k {
+	case boolCap:
+		return "boolean"
+	case numCap:
+		return "numeric"
+	case strCap:
+		return "string"
+	}
+	return fmt.Sprintf("capKind(%q)", byte(k))
+}
+
+// capIndex is where a capability name lives in a TermInfo.
+type capIndex struct {
+	kind capKind
+	idx  int
+}
+
+// capsByName maps all the standard capnames to their indexes.
+var capsByName = func() map[string]capIndex {
+	m := make(map[string]capIndex, len(booleanCapNames)+len(numberCapNames)+len(stringCapNames))
+	for i, name := range booleanCapNames {
+		m[name] = capIndex{boolCap, i}
+	}
+	for i, name := range numberCapNames {
+		m[name] = capIndex{numCap, i}
+	}
+	for i, name := range stringCapNames {
+		m[name] = capIndex{strCap, i}
+	}
+	return m
+}()
+
+// sourceCap is a capability as given in terminfo source.
+type sourceCap struct {
+	kind      capKind
+	cancelled bool
+	num       int32
+	str       []byte
+}
+
+type sourceEntry struct {
+	names string
+	line  int
+	// the capabilities, by name, as given in the entry itself
+	caps map[string]sourceCap
+	// the capabilities inherited through use=, in order
+	uses []string
+	// set once the uses have been merged in
+	resolved  bool
+	resolving bool
+}
+
+// ParseSource parses terminfo source, as described in terminfo(5), and
+// returns the entries in it. Capabilities not in the standard tables are
+// taken to be ncurses extended capabilities, as tic -x does. use=
+// capabilities are resolved against the other entries in the source
+// first and the terminfo database second.
+func ParseSource(r io.Reader) ([]*TermInfo, error) {
+	entries, err := scanSource(r)
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]*sourceEntry)
+	for _, e := range entries {
+		for _, name := range strings.Split(e.names, "|") {
+			byName[name] = e
+		}
+	}
+
+	tis := make([]*TermInfo, len(entries))
+	for i, e := range entries {
+		if err := e.resolve(byName); err != nil {
+			return nil, err
+		}
+		tis[i] = e.termInfo()
+	}
+
+	return tis, nil
+}
+
+// scanSource splits terminfo source into entries, and their entries
+// into capabilities.
+func scanSource(r io.Reader) ([]*sourceEntry, error) {
+	var entries []*sourceEntry
+	var text strings.Builder
+	var lines []int // the line each line of text came from
+	flush := func() error {
+		if len(lines) == 0 {
+			return nil
+		}
+		e, err := parseEntry(text.String(), lines)
+		text.Reset()
+		lines = nil
+		if err != nil {
+			return err
+		}
+		entries = append(entries, e)
+		return nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "#"), strings.TrimSpace(line) == "":
+			// comment or blank line
+			continue
+		case line[0] != ' ' && line[0] != '\t':
+			// the start of a new entry
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		case len(lines) == 0:
+			return nil, &ErrBadSource{Line: n, Err: fmt.Errorf("continuation line outside of an entry")}
+		}
+		text.WriteString(line)
+		text.WriteByte('\n')
+		lines = append(lines, n)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// parseEntry parses the text of an entry; lines says which line of the
+// source each line of text came from.
+func parseEntry(text string, lines []int) (*sourceEntry, error) {
+	e := &sourceEntry{line: lines[0], caps: make(map[string]sourceCap)}
+
+	var field []byte
+	nl := 0
+	fieldLine := lines[0]
+	first := true
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch c {
+		case '^':
+			// ^X is a unit, even if X is a \ or a comma; %^ is the xor
+			// operator, though, as in tic
+			field = append(field, c)
+			if (i == 0 || text[i-1] != '%') && i+1 < len(text) && text[i+1] != '\n' {
+				i++
+				field = append(field, text[i])
+			}
+			continue
+		case '\\':
+			field = append(field, c)
+			if i+1 < len(text) {
+				i++
+				field = append(field, text[i])
+			}
+			continue
+		case '\n':
+			nl++
+			if len(bytes.TrimSpace(field)) == 0 {
+				field = field[:0]
+				if nl < len(lines) {
+					fieldLine = lines[nl]
+				}
+			}
+			continue
+		case ',':
+		default:
+			field = append(field, c)
+			continue
+		}
+
+		f := string(bytes.TrimLeft(field, " \t"))
+		field = field[:0]
+		if first {
+			e.names = strings.TrimSpace(f)
+			if e.names == "" {
+				return nil, &ErrBadSource{Line: fieldLine, Err: fmt.Errorf("missing names")}
+			}
+			first = false
+		} else if err := e.addCap(f); err != nil {
+			return nil, &ErrBadSource{Line: fieldLine, Err: err}
+		}
+		if nl < len(lines) {
+			fieldLine = lines[nl]
+		}
+	}
+	if first {
+		return nil, &ErrBadSource{Line: e.line, Err: fmt.Errorf("missing comma after names")}
+	}
+	if rest := bytes.TrimSpace(field); len(rest) > 0 {
+		return nil, &ErrBadSource{Line: fieldLine, Err: fmt.Errorf("missing comma after %q", rest)}
+	}
+
+	return e, nil
+}
+
+// addCap parses a single capability, as found between commas.
+func (e *sourceEntry) addCap(f string) error {
+	if f == "" || f[0] == '.' {
+		// empty, or commented out
+		return nil
+	}
+
+	var name string
+	var cap sourceCap
+	if i := strings.IndexAny(f, "=#@"); i < 0 {
+		name = strings.TrimSpace(f)
+		cap.kind = boolCap
+	} else {
+		name = f[:i]
+		switch f[i] {
+		case '@':
+			if rest := strings.TrimSpace(f[i+1:]); rest != "" {
+				return fmt.Errorf("junk after cancelled %q: %q", name, rest)
+			}
+			cap.cancelled = true
+		case '#':
+			cap.kind = numCap
+			n, err := parseSourceNumber(strings.TrimSpace(f[i+1:]))
+			if err != nil || n < 0 {
+				return fmt.Errorf("bad number for %q: %q", name, f[i+1:])
+			}
+			cap.num = int32(n)
+		case '=':
+			cap.kind = strCap
+			str, err := unescapeSource(f[i+1:])
+			if err != nil {
+				return fmt.Errorf("bad string for %q: %v", name, err)
+			}
+			cap.str = str
+		}
+	}
+	if name == "" || strings.ContainsAny(name, " \t") {
+		return fmt.Errorf("bad capability name %q", name)
+	}
+
+	if name == "use" {
+		if cap.kind != strCap {
+			return fmt.Errorf("use needs a terminal name")
+		}
+		e.uses = append(e.uses, string(cap.str))
+		return nil
+	}
+	if idx, ok := capsByName[name]; ok {
+		if cap.cancelled {
+			cap.kind = idx.kind
+		} else if cap.kind != idx.kind {
+			return fmt.Errorf("%q is a %v capability, not %v", name, idx.kind, cap.kind)
+		}
+	}
+	if _, ok := e.caps[name]; !ok {
+		// the first one wins, as with tic
+		e.caps[name] = cap
+	}
+
+	return nil
+}
+
+// resolve merges the capabilities of the entries this one uses into its
+// own. Capabilities given (or cancelled) in the entry itself win over
+// inherited ones, and earlier uses win over later ones.
+func (e *sourceEntry) resolve(byName map[string]*sourceEntry) error {
+	if e.resolved {
+		return nil
+	}
+	if e.resolving {
+		return &ErrBadSource{Line: e.line, Err: fmt.Errorf("use= loop involving %q", e.names)}
+	}
+	e.resolving = true
+	defer func() { e.resolving = false }()
+
+	for _, use := range e.uses {
+		var caps map[string]sourceCap
+		if base, ok := byName[use]; ok {
+			if err := base.resolve(byName); err != nil {
+				return err
+			}
+			caps = base.caps
+		} else {
+			ti, err := LoadTermF(use, os.Stdout)
+			if err != nil {
+				return &ErrBadSource{Line: e.line, Err: fmt.Errorf("use=%s: %v", use, err)}
+			}
+			caps = ti.sourceCaps()
+		}
+		for name, cap := range caps {
+			if _, ok := e.caps[name]; !ok {
+				e.caps[name] = cap
+			}
+		}
+	}
+	e.resolved = true
+
+	return nil
+}
+
+// sourceCaps returns the TermInfo's capabilities, by name.
+func (ti *TermInfo) sourceCaps() map[string]sourceCap {
+	caps := make(map[string]sourceCap)
+	for i, b := range ti.Booleans {
+		if b && i < len(booleanCapNames) {
+			caps[booleanCapNames[i]] = sourceCap{kind: boolCap}
+		}
+	}
+	for i, n := range ti.numbers() {
+		if n >= 0 && i < len(numberCapNames) {
+			caps[numberCapNames[i]] = sourceCap{kind: numCap, num: n}
+		}
+	}
+	for i, str := range ti.Strings {
+		if int(i) < len(stringCapNames) {
+			caps[stringCapNames[i]] = sourceCap{kind: strCap, str: str}
+		}
+	}
+	for name, b := range ti.ExtBooleans {
+		if b {
+			caps[name] = sourceCap{kind: boolCap}
+		}
+	}
+	for name, n := range ti.ExtNumbers {
+		caps[name] = sourceCap{kind: numCap, num: n}
+	}
+	for name, str := range ti.ExtStrings {
+		caps[name] = sourceCap{kind: strCap, str: str}
+	}
+	return caps
+}
+
+// termInfo builds a TermInfo out of a resolved entry, laid out as tic
+// would: sections only as long as needed, and 32-bit numbers only if
+// some don't fit in 16 bits.
+func (e *sourceEntry) termInfo() *TermInfo {
+	ti := &TermInfo{
+		Names:   strings.Split(e.names, "|"),
+		Strings: make(map[StringIndex][]byte),
+		tty:     os.Stdout,
+	}
+	var nums []int32
+	isBig := false
+
+	for name, cap := range e.caps {
+		if cap.cancelled {
+			continue
+		}
+		idx, ok := capsByName[name]
+		if !ok {
+			switch cap.kind {
+			case boolCap:
+				if ti.ExtBooleans == nil {
+					ti.ExtBooleans = make(map[string]bool)
+				}
+				ti.ExtBooleans[name] = true
+			case numCap:
+				if ti.ExtNumbers == nil {
+					ti.ExtNumbers = make(map[string]int32)
+				}
+				ti.ExtNumbers[name] = cap.num
+				isBig = isBig || cap.num > math.MaxInt16
+			case strCap:
+				if ti.ExtStrings == nil {
+					ti.ExtStrings = make(map[string][]byte)
+				}
+				ti.ExtStrings[name] = cap.str
+			}
+			continue
+		}
+		switch idx.kind {
+		case boolCap:
+			for len(ti.Booleans) <= idx.idx {
+				ti.Booleans = append(ti.Booleans, false)
+			}
+			ti.Booleans[idx.idx] = true
+		case numCap:
+			for len(nums) <= idx.idx {
+				nums = append(nums, -1)
+			}
+			nums[idx.idx] = cap.num
+			isBig = isBig || cap.num > math.MaxInt16
+		case strCap:
+			ti.Strings[StringIndex(idx.idx)] = cap.str
+		}
+	}
+	if ti.ExtBooleans != nil || ti.ExtNumbers != nil || ti.ExtStrings != nil {
+		// as when reading a compiled entry with extended
+		// capabilities, all three maps are there.
+		if ti.ExtBooleans == nil {
+			ti.ExtBooleans = make(map[string]bool)
+		}
+		if ti.ExtNumbers == nil {
+			ti.ExtNumbers = make(map[string]int32)
+		}
+		if ti.ExtStrings == nil {
+			ti.ExtStrings = make(map[string][]byte)
+		}
+	}
+
+	if isBig {
+		ti.BigNumbers = nums
+	} else {
+		ti.Numbers = make([]int16, len(nums))
+		for i, n := range nums {
+			ti.Numbers[i] = int16(n)
+		}
+	}
+
+	return ti
+}
+
+// parseSourceNumber parses a numeric capability's value as tic does,
+// with strtol: in decimal, or in octal with a leading 0, or in hex with
+// a leading 0x. Go's other prefixes and underscores aren't allowed.
+func parseSourceNumber(s string) (int64, error) {
+	base, digits := 10, s
+	switch {
+	case len(s) > 2 && (s[:2] == "0x" || s[:2] == "0X"):
+		base, digits = 16, s[2:]
+	case len(s) > 1 && s[0] == '0':
+		base, digits = 8, s[1:]
+	}
+	if strings.ContainsAny(digits, "_+-") {
+		return 0, strconv.ErrSyntax
+	}
+	return strconv.ParseInt(digits, base, 32)
+}
+
+// unescapeSource decodes the escapes in a string capability's value,
+// as described in terminfo(5). Nulls are stored as \200, as ncurses
+// does.
+func unescapeSource(s string) ([]byte, error) {
+	var buf []byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch c {
+		case '^':
+			if i > 0 && s[i-1] == '%' {
+				// %^ is the xor operator, and tic keeps it as is
+				break
+			}
+			i++
+			if i >= len(s) {
+				return nil, fmt.Errorf("dangling ^")
+			}
+			switch c = s[i]; {
+			case c == '?':
+				c = 0177
+			case c == '@':
+				c = 0200
+			default:
+				c &= 037
+				if c == 0 {
+					c = 0200
+				}
+			}
+		case '\\':
+			i++
+			if i >= len(s) {
+				return nil, fmt.Errorf("dangling \\")
+			}
+			switch c = s[i]; c {
+			case 'E', 'e':
+				c = 033
+			case 'a':
+				c = 007
+			case 'n', 'l':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'f':
+				c = '\f'
+			case 's':
+				c = ' '
+			case '^', '\\', ',', ':':
+				// as is
+			case '0', '1', '2', '3', '4', '5', '6', '7':
+				n := 0
+				j := i
+				for ; j < len(s) && j < i+3 && s[j] >= '0' && s[j] <= '7'; j++ {
+					n = n*8 + int(s[j]-'0')
+				}
+				i = j - 1
+				c = byte(n)
+				if c == 0 {
+					c = 0200
+				}
+			default:
+				// tic warns about these, but keeps the
+				// character as is
+			}
+		}
+		buf = append(buf, c)
+	}
+	return buf, nil
+}
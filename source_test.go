@@ -0,0 +1,127 @@
+package terminfo_test
+
+import (
+	"os"
+	"strings"
+
+	"gopkg.in/check.v1"
+
+	"gopkg.in/terminfo.v0"
+)
+
+func parseSource(c *check.C, src string) []*terminfo.TermInfo {
+	tis, err := terminfo.ParseSource(strings.NewReader(src))
+	c.Assert(err, check.IsNil)
+	return tis
+}
+
+func (*tiSuite) TestParseSourceLikeTic(c *check.C) {
+	for _, s := range []struct {
+		src   string
+		terms []string
+	}{
+		{"testdata/terminfo.src", []string{"ext-test", "ext-direct"}},
+		{"testdata/xterm-256color.src", []string{"xterm-256color"}},
+	} {
+		f, err := os.Open(s.src)
+		c.Assert(err, check.IsNil)
+		tis, err := terminfo.ParseSource(f)
+		f.Close()
+		c.Assert(err, check.IsNil)
+		c.Assert(tis, check.HasLen, len(s.terms))
+		for i, term := range s.terms {
+			c.Check(tis[i], check.DeepEquals, loadTestdata(c, term), check.Commentf(term))
+		}
+	}
+}
+
+func (*tiSuite) TestParseSourceEscapes(c *check.C) {
+	tis := parseSource(c, `# a comment
+esc|escapes,
+	u0=\E\e^[, u1=^A^a^?^@^^, u2=\0\000\200\054\,\:\s\^\\,
+	u3=\n\l\r\t\b\f\a, u4=$<5*/>%p1%d, u5=%p1%p2%^%d,
+`)
+	c.Assert(tis, check.HasLen, 1)
+	c.Check(tis[0].Names, check.DeepEquals, []string{"esc", "escapes"})
+	for idx, s := range map[terminfo.StringIndex]string{
+		terminfo.User0: "\x1b\x1b\x1b",
+		terminfo.User1: "\x01\x01\x7f\x80\x1e",
+		terminfo.User2: "\x80\x80\x80,,: ^\\",
+		terminfo.User3: "\n\n\r\t\b\f\a",
+		terminfo.User4: "$<5*/>%p1%d",
+		terminfo.User5: "%p1%p2%^%d",
+	} {
+		c.Check(string(tis[0].Strings[idx]), check.Equals, s, check.Commentf("%v", idx))
+	}
+
+	// ^\ is a control character, and the comma after it ends the field
+	tis = parseSource(c, `ctlbs|t,
+	cuf1=^\, cup=A, u0=a^\b, u1=%p1%p2%^%d,
+`)
+	c.Assert(tis, check.HasLen, 1)
+	for idx, s := range map[terminfo.StringIndex]string{
+		terminfo.CursorRight:   "\x1c",
+		terminfo.CursorAddress: "A",
+		terminfo.User0:         "a\x1cb",
+		terminfo.User1:         "%p1%p2%^%d",
+	} {
+		c.Check(string(tis[0].Strings[idx]), check.Equals, s, check.Commentf("%v", idx))
+	}
+}
+
+func (*tiSuite) TestParseSourceUse(c *check.C) {
+	tis := parseSource(c, `
+base|the base,
+	am, cols#80, bel=^G, cr=\r, Foo,
+derived|uses base and cancels some,
+	cols#132, bel@, Foo@, use=base, xenl,
+more|uses both,
+	cr=\n, use=derived, use=base,
+`)
+	c.Assert(tis, check.HasLen, 3)
+	derived := tis[1]
+	c.Check(derived.Booleans[terminfo.AutoRightMargin], check.Equals, true)
+	c.Check(derived.Booleans[terminfo.EatNewlineGlitch], check.Equals, true)
+	c.Check(derived.Numbers[terminfo.Columns], check.Equals, int16(132))
+	c.Check(derived.Strings[terminfo.Bell], check.IsNil)
+	c.Check(string(derived.Strings[terminfo.CarriageReturn]), check.Equals, "\r")
+	c.Check(derived.ExtBooleans, check.IsNil)
+
+	// cancelled in the first use wins over being there in the second
+	more := tis[2]
+	c.Check(more.Numbers[terminfo.Columns], check.Equals, int16(132))
+	c.Check(more.Strings[terminfo.Bell], check.IsNil)
+	c.Check(string(more.Strings[terminfo.CarriageReturn]), check.Equals, "\n")
+	c.Check(more.ExtBooleans["Foo"], check.Equals, false)
+
+	// use= of something not in the source goes to the database
+	defer os.Setenv("TERMINFO", os.Getenv("TERMINFO"))
+	os.Setenv("TERMINFO", "testdata")
+	// numbers are decimal, octal or hex, as with strtol
+	tis = parseSource(c, "num|numbers, cols#0x50, lines#030, it#8,\n")
+	c.Check(tis[0].Numbers, check.DeepEquals, []int16{80, 8, 24})
+
+	tis = parseSource(c, "mine|mine, U8#0, use=ext,\n")
+	c.Check(tis[0].ExtNumbers["U8"], check.Equals, int32(0))
+	c.Check(tis[0].ExtBooleans["Tc"], check.Equals, true)
+}
+
+func (*tiSuite) TestParseSourceErrors(c *check.C) {
+	for src, msg := range map[string]string{
+		"a|b, am,\n\tcup#3,\n":                `bad terminfo source at line 2: "cup" is a string capability, not numeric`,
+		"a|b, am,\n\tcols=3,\n":               `bad terminfo source at line 2: "cols" is a numeric capability, not string`,
+		"a|b, am,\n\tcols#x,\n":               `bad terminfo source at line 2: bad number for "cols": "x"`,
+		"a|b, am,\n\tcols#0b101,\n":           `bad terminfo source at line 2: bad number for "cols": "0b101"`,
+		"a|b, am,\n\tcols#0o17,\n":            `bad terminfo source at line 2: bad number for "cols": "0o17"`,
+		"a|b, am,\n\tcols#1_000,\n":           `bad terminfo source at line 2: bad number for "cols": "1_000"`,
+		"a|b, am,\n\tcols#089,\n":             `bad terminfo source at line 2: bad number for "cols": "089"`,
+		"a|b,\n\tam,\n\n\tcols\n":             `bad terminfo source at line 4: missing comma after "cols"`,
+		"\tam,\n":                             `bad terminfo source at line 1: continuation line outside of an entry`,
+		"a|b\n":                               `bad terminfo source at line 1: missing comma after names`,
+		"a|b, use=c,\nc|d, use=a,\n":          `bad terminfo source at line \d: use= loop involving .*`,
+		"a|b, use=no-such-terminal-at-all,\n": `bad terminfo source at line 1: use=no-such-terminal-at-all: .*`,
+	} {
+		_, err := terminfo.ParseSource(strings.NewReader(src))
+		c.Check(err, check.ErrorMatches, msg, check.Commentf("%q", src))
+	}
+}
@@ -420,6 +420,1701 @@ const (
 	BoxChars1
 	MaxStringIndex = BoxChars1
 )
+var stringCapNames = [...]string{
+	BackTab: "cbt",
+	Bell: "bel",
+	CarriageReturn: "cr",
+	ChangeScrollRegion: "csr",
+	ClearAllTabs: "tbc",
+	ClearScreen: "clear",
+	ClrEol: "el",
+	ClrEos: "ed",
+	ColumnAddress: "hpa",
+	CommandCharacter: "cmdch",
+	CursorAddress: "cup",
+	CursorDown: "cud1",
+	CursorHome: "home",
+	CursorInvisible: "civis",
+	CursorLeft: "cub1",
+	CursorMemAddress: "mrcup",
+	CursorNormal: "cnorm",
+	CursorRight: "cuf1",
+	CursorToLl: "ll",
+	CursorUp: "cuu1",
+	CursorVisible: "cvvis",
+	DeleteCharacter: "dch1",
+	DeleteLine: "dl1",
+	DisStatusLine: "dsl",
+	DownHalfLine: "hd",
+	EnterAltCharsetMode: "smacs",
+	EnterBlinkMode: "blink",
+	EnterBoldMode: "bold",
+	EnterCaMode: "smcup",
+	EnterDeleteMode: "smdc",
+	EnterDimMode: "dim",
+	EnterInsertMode: "smir",
+	EnterSecureMode: "invis",
+	EnterProtectedMode: "prot",
+	EnterReverseMode: "rev",
+	EnterStandoutMode: "smso",
+	EnterUnderlineMode: "smul",
+	EraseChars: "ech",
+	ExitAltCharsetMode: "rmacs",
+	ExitAttributeMode: "sgr0",
+	ExitCaMode: "rmcup",
+	ExitDeleteMode: "rmdc",
+	ExitInsertMode: "rmir",
+	ExitStandoutMode: "rmso",
+	ExitUnderlineMode: "rmul",
+	FlashScreen: "flash",
+	FormFeed: "ff",
+	FromStatusLine: "fsl",
+	Init1string: "is1",
+	Init2string: "is2",
+	Init3string: "is3",
+	InitFile: "if",
+	InsertCharacter: "ich1",
+	InsertLine: "il1",
+	InsertPadding: "ip",
+	KeyBackspace: "kbs",
+	KeyCatab: "ktbc",
+	KeyClear: "kclr",
+	KeyCtab: "kctab",
+	KeyDc: "kdch1",
+	KeyDl: "kdl1",
+	KeyDown: "kcud1",
+	KeyEic: "krmir",
+	KeyEol: "kel",
+	KeyEos: "ked",
+	KeyF0: "kf0",
+	KeyF1: "kf1",
+	KeyF10: "kf10",
+	KeyF2: "kf2",
+	KeyF3: "kf3",
+	KeyF4: "kf4",
+	KeyF5: "kf5",
+	KeyF6: "kf6",
+	KeyF7: "kf7",
+	KeyF8: "kf8",
+	KeyF9: "kf9",
+	KeyHome: "khome",
+	KeyIc: "kich1",
+	KeyIl: "kil1",
+	KeyLeft: "kcub1",
+	KeyLl: "kll",
+	KeyNpage: "knp",
+	KeyPpage: "kpp",
+	KeyRight: "kcuf1",
+	KeySf: "kind",
+	KeySr: "kri",
+	KeyStab: "khts",
+	KeyUp: "kcuu1",
+	KeypadLocal: "rmkx",
+	KeypadXmit: "smkx",
+	LabF0: "lf0",
+	LabF1: "lf1",
+	LabF10: "lf10",
+	LabF2: "lf2",
+	LabF3: "lf3",
+	LabF4: "lf4",
+	LabF5: "lf5",
+	LabF6: "lf6",
+	LabF7: "lf7",
+	LabF8: "lf8",
+	LabF9: "lf9",
+	MetaOff: "rmm",
+	MetaOn: "smm",
+	Newline: "nel",
+	PadChar: "pad",
+	ParmDch: "dch",
+	ParmDeleteLine: "dl",
+	ParmDownCursor: "cud",
+	ParmIch: "ich",
+	ParmIndex: "indn",
+	ParmInsertLine: "il",
+	ParmLeftCursor: "cub",
+	ParmRightCursor: "cuf",
+	ParmRindex: "rin",
+	ParmUpCursor: "cuu",
+	PkeyKey: "pfkey",
+	PkeyLocal: "pfloc",
+	PkeyXmit: "pfx",
+	PrintScreen: "mc0",
+	PrtrOff: "mc4",
+	PrtrOn: "mc5",
+	RepeatChar: "rep",
+	Reset1string: "rs1",
+	Reset2string: "rs2",
+	Reset3string: "rs3",
+	ResetFile: "rf",
+	RestoreCursor: "rc",
+	RowAddress: "vpa",
+	SaveCursor: "sc",
+	ScrollForward: "ind",
+	ScrollReverse: "ri",
+	SetAttributes: "sgr",
+	SetTab: "hts",
+	SetWindow: "wind",
+	Tab: "ht",
+	ToStatusLine: "tsl",
+	UnderlineChar: "uc",
+	UpHalfLine: "hu",
+	InitProg: "iprog",
+	KeyA1: "ka1",
+	KeyA3: "ka3",
+	KeyB2: "kb2",
+	KeyC1: "kc1",
+	KeyC3: "kc3",
+	PrtrNon: "mc5p",
+	CharPadding: "rmp",
+	AcsChars: "acsc",
+	PlabNorm: "pln",
+	KeyBtab: "kcbt",
+	EnterXonMode: "smxon",
+	ExitXonMode: "rmxon",
+	EnterAmMode: "smam",
+	ExitAmMode: "rmam",
+	XonCharacter: "xonc",
+	XoffCharacter: "xoffc",
+	EnaAcs: "enacs",
+	LabelOn: "smln",
+	LabelOff: "rmln",
+	KeyBeg: "kbeg",
+	KeyCancel: "kcan",
+	KeyClose: "kclo",
+	KeyCommand: "kcmd",
+	KeyCopy: "kcpy",
+	KeyCreate: "kcrt",
+	KeyEnd: "kend",
+	KeyEnter: "kent",
+	KeyExit: "kext",
+	KeyFind: "kfnd",
+	KeyHelp: "khlp",
+	KeyMark: "kmrk",
+	KeyMessage: "kmsg",
+	KeyMove: "kmov",
+	KeyNext: "knxt",
+	KeyOpen: "kopn",
+	KeyOptions: "kopt",
+	KeyPrevious: "kprv",
+	KeyPrint: "kprt",
+	KeyRedo: "krdo",
+	KeyReference: "kref",
+	KeyRefresh: "krfr",
+	KeyReplace: "krpl",
+	KeyRestart: "krst",
+	KeyResume: "kres",
+	KeySave: "ksav",
+	KeySuspend: "kspd",
+	KeyUndo: "kund",
+	KeySbeg: "kBEG",
+	KeyScancel: "kCAN",
+	KeyScommand: "kCMD",
+	KeyScopy: "kCPY",
+	KeyScreate: "kCRT",
+	KeySdc: "kDC",
+	KeySdl: "kDL",
+	KeySelect: "kslt",
+	KeySend: "kEND",
+	KeySeol: "kEOL",
+	KeySexit: "kEXT",
+	KeySfind: "kFND",
+	KeyShelp: "kHLP",
+	KeyShome: "kHOM",
+	KeySic: "kIC",
+	KeySleft: "kLFT",
+	KeySmessage: "kMSG",
+	KeySmove: "kMOV",
+	KeySnext: "kNXT",
+	KeySoptions: "kOPT",
+	KeySprevious: "kPRV",
+	KeySprint: "kPRT",
+	KeySredo: "kRDO",
+	KeySreplace: "kRPL",
+	KeySright: "kRIT",
+	KeySrsume: "kRES",
+	KeySsave: "kSAV",
+	KeySsuspend: "kSPD",
+	KeySundo: "kUND",
+	ReqForInput: "rfi",
+	KeyF11: "kf11",
+	KeyF12: "kf12",
+	KeyF13: "kf13",
+	KeyF14: "kf14",
+	KeyF15: "kf15",
+	KeyF16: "kf16",
+	KeyF17: "kf17",
+	KeyF18: "kf18",
+	KeyF19: "kf19",
+	KeyF20: "kf20",
+	KeyF21: "kf21",
+	KeyF22: "kf22",
+	KeyF23: "kf23",
+	KeyF24: "kf24",
+	KeyF25: "kf25",
+	KeyF26: "kf26",
+	KeyF27: "kf27",
+	KeyF28: "kf28",
+	KeyF29: "kf29",
+	KeyF30: "kf30",
+	KeyF31: "kf31",
+	KeyF32: "kf32",
+	KeyF33: "kf33",
+	KeyF34: "kf34",
+	KeyF35: "kf35",
+	KeyF36: "kf36",
+	KeyF37: "kf37",
+	KeyF38: "kf38",
+	KeyF39: "kf39",
+	KeyF40: "kf40",
+	KeyF41: "kf41",
+	KeyF42: "kf42",
+	KeyF43: "kf43",
+	KeyF44: "kf44",
+	KeyF45: "kf45",
+	KeyF46: "kf46",
+	KeyF47: "kf47",
+	KeyF48: "kf48",
+	KeyF49: "kf49",
+	KeyF50: "kf50",
+	KeyF51: "kf51",
+	KeyF52: "kf52",
+	KeyF53: "kf53",
+	KeyF54: "kf54",
+	KeyF55: "kf55",
+	KeyF56: "kf56",
+	KeyF57: "kf57",
+	KeyF58: "kf58",
+	KeyF59: "kf59",
+	KeyF60: "kf60",
+	KeyF61: "kf61",
+	KeyF62: "kf62",
+	KeyF63: "kf63",
+	ClrBol: "el1",
+	ClearMargins: "mgc",
+	SetLeftMargin: "smgl",
+	SetRightMargin: "smgr",
+	LabelFormat: "fln",
+	SetClock: "sclk",
+	DisplayClock: "dclk",
+	RemoveClock: "rmclk",
+	CreateWindow: "cwin",
+	GotoWindow: "wingo",
+	Hangup: "hup",
+	DialPhone: "dial",
+	QuickDial: "qdial",
+	Tone: "tone",
+	Pulse: "pulse",
+	FlashHook: "hook",
+	FixedPause: "pause",
+	WaitTone: "wait",
+	User0: "u0",
+	User1: "u1",
+	User2: "u2",
+	User3: "u3",
+	User4: "u4",
+	User5: "u5",
+	User6: "u6",
+	User7: "u7",
+	User8: "u8",
+	User9: "u9",
+	OrigPair: "op",
+	OrigColors: "oc",
+	InitializeColor: "initc",
+	InitializePair: "initp",
+	SetColorPair: "scp",
+	SetForeground: "setf",
+	SetBackground: "setb",
+	ChangeCharPitch: "cpi",
+	ChangeLinePitch: "lpi",
+	ChangeResHorz: "chr",
+	ChangeResVert: "cvr",
+	DefineChar: "defc",
+	EnterDoublewideMode: "swidm",
+	EnterDraftQuality: "sdrfq",
+	EnterItalicsMode: "sitm",
+	EnterLeftwardMode: "slm",
+	EnterMicroMode: "smicm",
+	EnterNearLetterQuality: "snlq",
+	EnterNormalQuality: "snrmq",
+	EnterShadowMode: "sshm",
+	EnterSubscriptMode: "ssubm",
+	EnterSuperscriptMode: "ssupm",
+	EnterUpwardMode: "sum",
+	ExitDoublewideMode: "rwidm",
+	ExitItalicsMode: "ritm",
+	ExitLeftwardMode: "rlm",
+	ExitMicroMode: "rmicm",
+	ExitShadowMode: "rshm",
+	ExitSubscriptMode: "rsubm",
+	ExitSuperscriptMode: "rsupm",
+	ExitUpwardMode: "rum",
+	MicroColumnAddress: "mhpa",
+	MicroDown: "mcud1",
+	MicroLeft: "mcub1",
+	MicroRight: "mcuf1",
+	MicroRowAddress: "mvpa",
+	MicroUp: "mcuu1",
+	OrderOfPins: "porder",
+	ParmDownMicro: "mcud",
+	ParmLeftMicro: "mcub",
+	ParmRightMicro: "mcuf",
+	ParmUpMicro: "mcuu",
+	SelectCharSet: "scs",
+	SetBottomMargin: "smgb",
+	SetBottomMarginParm: "smgbp",
+	SetLeftMarginParm: "smglp",
+	SetRightMarginParm: "smgrp",
+	SetTopMargin: "smgt",
+	SetTopMarginParm: "smgtp",
+	StartBitImage: "sbim",
+	StartCharSetDef: "scsd",
+	StopBitImage: "rbim",
+	StopCharSetDef: "rcsd",
+	SubscriptCharacters: "subcs",
+	SuperscriptCharacters: "supcs",
+	TheseCauseCr: "docr",
+	ZeroMotion: "zerom",
+	CharSetNames: "csnm",
+	KeyMouse: "kmous",
+	MouseInfo: "minfo",
+	ReqMousePos: "reqmp",
+	GetMouse: "getm",
+	SetAForeground: "setaf",
+	SetABackground: "setab",
+	PkeyPlab: "pfxl",
+	DeviceType: "devt",
+	CodeSetInit: "csin",
+	Set0DesSeq: "s0ds",
+	Set1DesSeq: "s1ds",
+	Set2DesSeq: "s2ds",
+	Set3DesSeq: "s3ds",
+	SetLrMargin: "smglr",
+	SetTbMargin: "smgtb",
+	BitImageRepeat: "birep",
+	BitImageNewline: "binel",
+	BitImageCarriageReturn: "bicr",
+	ColorNames: "colornm",
+	DefineBitImageRegion: "defbi",
+	EndBitImageRegion: "endbi",
+	SetColorBand: "setcolor",
+	SetPageLength: "slines",
+	DisplayPcChar: "dispc",
+	EnterPcCharsetMode: "smpch",
+	ExitPcCharsetMode: "rmpch",
+	EnterScancodeMode: "smsc",
+	ExitScancodeMode: "rmsc",
+	PcTermOptions: "pctrm",
+	ScancodeEscape: "scesc",
+	AltScancodeEsc: "scesa",
+	EnterHorizontalHlMode: "ehhlm",
+	EnterLeftHlMode: "elhlm",
+	EnterLowHlMode: "elohlm",
+	EnterRightHlMode: "erhlm",
+	EnterTopHlMode: "ethlm",
+	EnterVerticalHlMode: "evhlm",
+	SetAAttributes: "sgr1",
+	SetPglenInch: "slength",
+	TermcapInit2: "OTi2",
+	TermcapReset: "OTrs",
+	LinefeedIfNotLf: "OTnl",
+	BackspaceIfNotBs: "OTbc",
+	OtherNonFunctionKeys: "OTko",
+	ArrowKeyMap: "OTma",
+	AcsUlcorner: "OTG2",
+	AcsLlcorner: "OTG3",
+	AcsUrcorner: "OTG1",
+	AcsLrcorner: "OTG4",
+	AcsLtee: "OTGR",
+	AcsRtee: "OTGL",
+	AcsBtee: "OTGU",
+	AcsTtee: "OTGD",
+	AcsHline: "OTGH",
+	AcsVline: "OTGV",
+	AcsPlus: "OTGC",
+	MemoryLock: "meml",
+	MemoryUnlock: "memu",
+	BoxChars1: "box1",
+}
+var stringTermcapNames = [...]string{
+	BackTab: "bt",
+	Bell: "bl",
+	CarriageReturn: "cr",
+	ChangeScrollRegion: "cs",
+	ClearAllTabs: "ct",
+	ClearScreen: "cl",
+	ClrEol: "ce",
+	ClrEos: "cd",
+	ColumnAddress: "ch",
+	CommandCharacter: "CC",
+	CursorAddress: "cm",
+	CursorDown: "do",
+	CursorHome: "ho",
+	CursorInvisible: "vi",
+	CursorLeft: "le",
+	CursorMemAddress: "CM",
+	CursorNormal: "ve",
+	CursorRight: "nd",
+	CursorToLl: "ll",
+	CursorUp: "up",
+	CursorVisible: "vs",
+	DeleteCharacter: "dc",
+	DeleteLine: "dl",
+	DisStatusLine: "ds",
+	DownHalfLine: "hd",
+	EnterAltCharsetMode: "as",
+	EnterBlinkMode: "mb",
+	EnterBoldMode: "md",
+	EnterCaMode: "ti",
+	EnterDeleteMode: "dm",
+	EnterDimMode: "mh",
+	EnterInsertMode: "im",
+	EnterSecureMode: "mk",
+	EnterProtectedMode: "mp",
+	EnterReverseMode: "mr",
+	EnterStandoutMode: "so",
+	EnterUnderlineMode: "us",
+	EraseChars: "ec",
+	ExitAltCharsetMode: "ae",
+	ExitAttributeMode: "me",
+	ExitCaMode: "te",
+	ExitDeleteMode: "ed",
+	ExitInsertMode: "ei",
+	ExitStandoutMode: "se",
+	ExitUnderlineMode: "ue",
+	FlashScreen: "vb",
+	FormFeed: "ff",
+	FromStatusLine: "fs",
+	Init1string: "i1",
+	Init2string: "is",
+	Init3string: "i3",
+	InitFile: "if",
+	InsertCharacter: "ic",
+	InsertLine: "al",
+	InsertPadding: "ip",
+	KeyBackspace: "kb",
+	KeyCatab: "ka",
+	KeyClear: "kC",
+	KeyCtab: "kt",
+	KeyDc: "kD",
+	KeyDl: "kL",
+	KeyDown: "kd",
+	KeyEic: "kM",
+	KeyEol: "kE",
+	KeyEos: "kS",
+	KeyF0: "k0",
+	KeyF1: "k1",
+	KeyF10: "k;",
+	KeyF2: "k2",
+	KeyF3: "k3",
+	KeyF4: "k4",
+	KeyF5: "k5",
+	KeyF6: "k6",
+	KeyF7: "k7",
+	KeyF8: "k8",
+	KeyF9: "k9",
+	KeyHome: "kh",
+	KeyIc: "kI",
+	KeyIl: "kA",
+	KeyLeft: "kl",
+	KeyLl: "kH",
+	KeyNpage: "kN",
+	KeyPpage: "kP",
+	KeyRight: "kr",
+	KeySf: "kF",
+	KeySr: "kR",
+	KeyStab: "kT",
+	KeyUp: "ku",
+	KeypadLocal: "ke",
+	KeypadXmit: "ks",
+	LabF0: "l0",
+	LabF1: "l1",
+	LabF10: "la",
+	LabF2: "l2",
+	LabF3: "l3",
+	LabF4: "l4",
+	LabF5: "l5",
+	LabF6: "l6",
+	LabF7: "l7",
+	LabF8: "l8",
+	LabF9: "l9",
+	MetaOff: "mo",
+	MetaOn: "mm",
+	Newline: "nw",
+	PadChar: "pc",
+	ParmDch: "DC",
+	ParmDeleteLine: "DL",
+	ParmDownCursor: "DO",
+	ParmIch: "IC",
+	ParmIndex: "SF",
+	ParmInsertLine: "AL",
+	ParmLeftCursor: "LE",
+	ParmRightCursor: "RI",
+	ParmRindex: "SR",
+	ParmUpCursor: "UP",
+	PkeyKey: "pk",
+	PkeyLocal: "pl",
+	PkeyXmit: "px",
+	PrintScreen: "ps",
+	PrtrOff: "pf",
+	PrtrOn: "po",
+	RepeatChar: "rp",
+	Reset1string: "r1",
+	Reset2string: "r2",
+	Reset3string: "r3",
+	ResetFile: "rf",
+	RestoreCursor: "rc",
+	RowAddress: "cv",
+	SaveCursor: "sc",
+	ScrollForward: "sf",
+	ScrollReverse: "sr",
+	SetAttributes: "sa",
+	SetTab: "st",
+	SetWindow: "wi",
+	Tab: "ta",
+	ToStatusLine: "ts",
+	UnderlineChar: "uc",
+	UpHalfLine: "hu",
+	InitProg: "iP",
+	KeyA1: "K1",
+	KeyA3: "K3",
+	KeyB2: "K2",
+	KeyC1: "K4",
+	KeyC3: "K5",
+	PrtrNon: "pO",
+	CharPadding: "rP",
+	AcsChars: "ac",
+	PlabNorm: "pn",
+	KeyBtab: "kB",
+	EnterXonMode: "SX",
+	ExitXonMode: "RX",
+	EnterAmMode: "SA",
+	ExitAmMode: "RA",
+	XonCharacter: "XN",
+	XoffCharacter: "XF",
+	EnaAcs: "eA",
+	LabelOn: "LO",
+	LabelOff: "LF",
+	KeyBeg: "@1",
+	KeyCancel: "@2",
+	KeyClose: "@3",
+	KeyCommand: "@4",
+	KeyCopy: "@5",
+	KeyCreate: "@6",
+	KeyEnd: "@7",
+	KeyEnter: "@8",
+	KeyExit: "@9",
+	KeyFind: "@0",
+	KeyHelp: "%1",
+	KeyMark: "%2",
+	KeyMessage: "%3",
+	KeyMove: "%4",
+	KeyNext: "%5",
+	KeyOpen: "%6",
+	KeyOptions: "%7",
+	KeyPrevious: "%8",
+	KeyPrint: "%9",
+	KeyRedo: "%0",
+	KeyReference: "&1",
+	KeyRefresh: "&2",
+	KeyReplace: "&3",
+	KeyRestart: "&4",
+	KeyResume: "&5",
+	KeySave: "&6",
+	KeySuspend: "&7",
+	KeyUndo: "&8",
+	KeySbeg: "&9",
+	KeyScancel: "&0",
+	KeyScommand: "*1",
+	KeyScopy: "*2",
+	KeyScreate: "*3",
+	KeySdc: "*4",
+	KeySdl: "*5",
+	KeySelect: "*6",
+	KeySend: "*7",
+	KeySeol: "*8",
+	KeySexit: "*9",
+	KeySfind: "*0",
+	KeyShelp: "#1",
+	KeyShome: "#2",
+	KeySic: "#3",
+	KeySleft: "#4",
+	KeySmessage: "%a",
+	KeySmove: "%b",
+	KeySnext: "%c",
+	KeySoptions: "%d",
+	KeySprevious: "%e",
+	KeySprint: "%f",
+	KeySredo: "%g",
+	KeySreplace: "%h",
+	KeySright: "%i",
+	KeySrsume: "%j",
+	KeySsave: "!1",
+	KeySsuspend: "!2",
+	KeySundo: "!3",
+	ReqForInput: "RF",
+	KeyF11: "F1",
+	KeyF12: "F2",
+	KeyF13: "F3",
+	KeyF14: "F4",
+	KeyF15: "F5",
+	KeyF16: "F6",
+	KeyF17: "F7",
+	KeyF18: "F8",
+	KeyF19: "F9",
+	KeyF20: "FA",
+	KeyF21: "FB",
+	KeyF22: "FC",
+	KeyF23: "FD",
+	KeyF24: "FE",
+	KeyF25: "FF",
+	KeyF26: "FG",
+	KeyF27: "FH",
+	KeyF28: "FI",
+	KeyF29: "FJ",
+	KeyF30: "FK",
+	KeyF31: "FL",
+	KeyF32: "FM",
+	KeyF33: "FN",
+	KeyF34: "FO",
+	KeyF35: "FP",
+	KeyF36: "FQ",
+	KeyF37: "FR",
+	KeyF38: "FS",
+	KeyF39: "FT",
+	KeyF40: "FU",
+	KeyF41: "FV",
+	KeyF42: "FW",
+	KeyF43: "FX",
+	KeyF44: "FY",
+	KeyF45: "FZ",
+	KeyF46: "Fa",
+	KeyF47: "Fb",
+	KeyF48: "Fc",
+	KeyF49: "Fd",
+	KeyF50: "Fe",
+	KeyF51: "Ff",
+	KeyF52: "Fg",
+	KeyF53: "Fh",
+	KeyF54: "Fi",
+	KeyF55: "Fj",
+	KeyF56: "Fk",
+	KeyF57: "Fl",
+	KeyF58: "Fm",
+	KeyF59: "Fn",
+	KeyF60: "Fo",
+	KeyF61: "Fp",
+	KeyF62: "Fq",
+	KeyF63: "Fr",
+	ClrBol: "cb",
+	ClearMargins: "MC",
+	SetLeftMargin: "ML",
+	SetRightMargin: "MR",
+	LabelFormat: "Lf",
+	SetClock: "SC",
+	DisplayClock: "DK",
+	RemoveClock: "RC",
+	CreateWindow: "CW",
+	GotoWindow: "WG",
+	Hangup: "HU",
+	DialPhone: "DI",
+	QuickDial: "QD",
+	Tone: "TO",
+	Pulse: "PU",
+	FlashHook: "fh",
+	FixedPause: "PA",
+	WaitTone: "WA",
+	User0: "u0",
+	User1: "u1",
+	User2: "u2",
+	User3: "u3",
+	User4: "u4",
+	User5: "u5",
+	User6: "u6",
+	User7: "u7",
+	User8: "u8",
+	User9: "u9",
+	OrigPair: "op",
+	OrigColors: "oc",
+	InitializeColor: "Ic",
+	InitializePair: "Ip",
+	SetColorPair: "sp",
+	SetForeground: "Sf",
+	SetBackground: "Sb",
+	ChangeCharPitch: "ZA",
+	ChangeLinePitch: "ZB",
+	ChangeResHorz: "ZC",
+	ChangeResVert: "ZD",
+	DefineChar: "ZE",
+	EnterDoublewideMode: "ZF",
+	EnterDraftQuality: "ZG",
+	EnterItalicsMode: "ZH",
+	EnterLeftwardMode: "ZI",
+	EnterMicroMode: "ZJ",
+	EnterNearLetterQuality: "ZK",
+	EnterNormalQuality: "ZL",
+	EnterShadowMode: "ZM",
+	EnterSubscriptMode: "ZN",
+	EnterSuperscriptMode: "ZO",
+	EnterUpwardMode: "ZP",
+	ExitDoublewideMode: "ZQ",
+	ExitItalicsMode: "ZR",
+	ExitLeftwardMode: "ZS",
+	ExitMicroMode: "ZT",
+	ExitShadowMode: "ZU",
+	ExitSubscriptMode: "ZV",
+	ExitSuperscriptMode: "ZW",
+	ExitUpwardMode: "ZX",
+	MicroColumnAddress: "ZY",
+	MicroDown: "ZZ",
+	MicroLeft: "Za",
+	MicroRight: "Zb",
+	MicroRowAddress: "Zc",
+	MicroUp: "Zd",
+	OrderOfPins: "Ze",
+	ParmDownMicro: "Zf",
+	ParmLeftMicro: "Zg",
+	ParmRightMicro: "Zh",
+	ParmUpMicro: "Zi",
+	SelectCharSet: "Zj",
+	SetBottomMargin: "Zk",
+	SetBottomMarginParm: "Zl",
+	SetLeftMarginParm: "Zm",
+	SetRightMarginParm: "Zn",
+	SetTopMargin: "Zo",
+	SetTopMarginParm: "Zp",
+	StartBitImage: "Zq",
+	StartCharSetDef: "Zr",
+	StopBitImage: "Zs",
+	StopCharSetDef: "Zt",
+	SubscriptCharacters: "Zu",
+	SuperscriptCharacters: "Zv",
+	TheseCauseCr: "Zw",
+	ZeroMotion: "Zx",
+	CharSetNames: "Zy",
+	KeyMouse: "Km",
+	MouseInfo: "Mi",
+	ReqMousePos: "RQ",
+	GetMouse: "Gm",
+	SetAForeground: "AF",
+	SetABackground: "AB",
+	PkeyPlab: "xl",
+	DeviceType: "dv",
+	CodeSetInit: "ci",
+	Set0DesSeq: "s0",
+	Set1DesSeq: "s1",
+	Set2DesSeq: "s2",
+	Set3DesSeq: "s3",
+	SetLrMargin: "ML",
+	SetTbMargin: "MT",
+	BitImageRepeat: "Xy",
+	BitImageNewline: "Zz",
+	BitImageCarriageReturn: "Yv",
+	ColorNames: "Yw",
+	DefineBitImageRegion: "Yx",
+	EndBitImageRegion: "Yy",
+	SetColorBand: "Yz",
+	SetPageLength: "YZ",
+	DisplayPcChar: "S1",
+	EnterPcCharsetMode: "S2",
+	ExitPcCharsetMode: "S3",
+	EnterScancodeMode: "S4",
+	ExitScancodeMode: "S5",
+	PcTermOptions: "S6",
+	ScancodeEscape: "S7",
+	AltScancodeEsc: "S8",
+	EnterHorizontalHlMode: "Xh",
+	EnterLeftHlMode: "Xl",
+	EnterLowHlMode: "Xo",
+	EnterRightHlMode: "Xr",
+	EnterTopHlMode: "Xt",
+	EnterVerticalHlMode: "Xv",
+	SetAAttributes: "sA",
+	SetPglenInch: "YI",
+	TermcapInit2: "i2",
+	TermcapReset: "rs",
+	LinefeedIfNotLf: "nl",
+	BackspaceIfNotBs: "bc",
+	OtherNonFunctionKeys: "ko",
+	ArrowKeyMap: "ma",
+	AcsUlcorner: "G2",
+	AcsLlcorner: "G3",
+	AcsUrcorner: "G1",
+	AcsLrcorner: "G4",
+	AcsLtee: "GR",
+	AcsRtee: "GL",
+	AcsBtee: "GU",
+	AcsTtee: "GD",
+	AcsHline: "GH",
+	AcsVline: "GV",
+	AcsPlus: "GC",
+	MemoryLock: "ml",
+	MemoryUnlock: "mu",
+	BoxChars1: "bx",
+}
+var stringsByCapName = map[string]StringIndex{
+	"cbt": BackTab,
+	"bel": Bell,
+	"cr": CarriageReturn,
+	"csr": ChangeScrollRegion,
+	"tbc": ClearAllTabs,
+	"clear": ClearScreen,
+	"el": ClrEol,
+	"ed": ClrEos,
+	"hpa": ColumnAddress,
+	"cmdch": CommandCharacter,
+	"cup": CursorAddress,
+	"cud1": CursorDown,
+	"home": CursorHome,
+	"civis": CursorInvisible,
+	"cub1": CursorLeft,
+	"mrcup": CursorMemAddress,
+	"cnorm": CursorNormal,
+	"cuf1": CursorRight,
+	"ll": CursorToLl,
+	"cuu1": CursorUp,
+	"cvvis": CursorVisible,
+	"dch1": DeleteCharacter,
+	"dl1": DeleteLine,
+	"dsl": DisStatusLine,
+	"hd": DownHalfLine,
+	"smacs": EnterAltCharsetMode,
+	"blink": EnterBlinkMode,
+	"bold": EnterBoldMode,
+	"smcup": EnterCaMode,
+	"smdc": EnterDeleteMode,
+	"dim": EnterDimMode,
+	"smir": EnterInsertMode,
+	"invis": EnterSecureMode,
+	"prot": EnterProtectedMode,
+	"rev": EnterReverseMode,
+	"smso": EnterStandoutMode,
+	"smul": EnterUnderlineMode,
+	"ech": EraseChars,
+	"rmacs": ExitAltCharsetMode,
+	"sgr0": ExitAttributeMode,
+	"rmcup": ExitCaMode,
+	"rmdc": ExitDeleteMode,
+	"rmir": ExitInsertMode,
+	"rmso": ExitStandoutMode,
+	"rmul": ExitUnderlineMode,
+	"flash": FlashScreen,
+	"ff": FormFeed,
+	"fsl": FromStatusLine,
+	"is1": Init1string,
+	"is2": Init2string,
+	"is3": Init3string,
+	"if": InitFile,
+	"ich1": InsertCharacter,
+	"il1": InsertLine,
+	"ip": InsertPadding,
+	"kbs": KeyBackspace,
+	"ktbc": KeyCatab,
+	"kclr": KeyClear,
+	"kctab": KeyCtab,
+	"kdch1": KeyDc,
+	"kdl1": KeyDl,
+	"kcud1": KeyDown,
+	"krmir": KeyEic,
+	"kel": KeyEol,
+	"ked": KeyEos,
+	"kf0": KeyF0,
+	"kf1": KeyF1,
+	"kf10": KeyF10,
+	"kf2": KeyF2,
+	"kf3": KeyF3,
+	"kf4": KeyF4,
+	"kf5": KeyF5,
+	"kf6": KeyF6,
+	"kf7": KeyF7,
+	"kf8": KeyF8,
+	"kf9": KeyF9,
+	"khome": KeyHome,
+	"kich1": KeyIc,
+	"kil1": KeyIl,
+	"kcub1": KeyLeft,
+	"kll": KeyLl,
+	"knp": KeyNpage,
+	"kpp": KeyPpage,
+	"kcuf1": KeyRight,
+	"kind": KeySf,
+	"kri": KeySr,
+	"khts": KeyStab,
+	"kcuu1": KeyUp,
+	"rmkx": KeypadLocal,
+	"smkx": KeypadXmit,
+	"lf0": LabF0,
+	"lf1": LabF1,
+	"lf10": LabF10,
+	"lf2": LabF2,
+	"lf3": LabF3,
+	"lf4": LabF4,
+	"lf5": LabF5,
+	"lf6": LabF6,
+	"lf7": LabF7,
+	"lf8": LabF8,
+	"lf9": LabF9,
+	"rmm": MetaOff,
+	"smm": MetaOn,
+	"nel": Newline,
+	"pad": PadChar,
+	"dch": ParmDch,
+	"dl": ParmDeleteLine,
+	"cud": ParmDownCursor,
+	"ich": ParmIch,
+	"indn": ParmIndex,
+	"il": ParmInsertLine,
+	"cub": ParmLeftCursor,
+	"cuf": ParmRightCursor,
+	"rin": ParmRindex,
+	"cuu": ParmUpCursor,
+	"pfkey": PkeyKey,
+	"pfloc": PkeyLocal,
+	"pfx": PkeyXmit,
+	"mc0": PrintScreen,
+	"mc4": PrtrOff,
+	"mc5": PrtrOn,
+	"rep": RepeatChar,
+	"rs1": Reset1string,
+	"rs2": Reset2string,
+	"rs3": Reset3string,
+	"rf": ResetFile,
+	"rc": RestoreCursor,
+	"vpa": RowAddress,
+	"sc": SaveCursor,
+	"ind": ScrollForward,
+	"ri": ScrollReverse,
+	"sgr": SetAttributes,
+	"hts": SetTab,
+	"wind": SetWindow,
+	"ht": Tab,
+	"tsl": ToStatusLine,
+	"uc": UnderlineChar,
+	"hu": UpHalfLine,
+	"iprog": InitProg,
+	"ka1": KeyA1,
+	"ka3": KeyA3,
+	"kb2": KeyB2,
+	"kc1": KeyC1,
+	"kc3": KeyC3,
+	"mc5p": PrtrNon,
+	"rmp": CharPadding,
+	"acsc": AcsChars,
+	"pln": PlabNorm,
+	"kcbt": KeyBtab,
+	"smxon": EnterXonMode,
+	"rmxon": ExitXonMode,
+	"smam": EnterAmMode,
+	"rmam": ExitAmMode,
+	"xonc": XonCharacter,
+	"xoffc": XoffCharacter,
+	"enacs": EnaAcs,
+	"smln": LabelOn,
+	"rmln": LabelOff,
+	"kbeg": KeyBeg,
+	"kcan": KeyCancel,
+	"kclo": KeyClose,
+	"kcmd": KeyCommand,
+	"kcpy": KeyCopy,
+	"kcrt": KeyCreate,
+	"kend": KeyEnd,
+	"kent": KeyEnter,
+	"kext": KeyExit,
+	"kfnd": KeyFind,
+	"khlp": KeyHelp,
+	"kmrk": KeyMark,
+	"kmsg": KeyMessage,
+	"kmov": KeyMove,
+	"knxt": KeyNext,
+	"kopn": KeyOpen,
+	"kopt": KeyOptions,
+	"kprv": KeyPrevious,
+	"kprt": KeyPrint,
+	"krdo": KeyRedo,
+	"kref": KeyReference,
+	"krfr": KeyRefresh,
+	"krpl": KeyReplace,
+	"krst": KeyRestart,
+	"kres": KeyResume,
+	"ksav": KeySave,
+	"kspd": KeySuspend,
+	"kund": KeyUndo,
+	"kBEG": KeySbeg,
+	"kCAN": KeyScancel,
+	"kCMD": KeyScommand,
+	"kCPY": KeyScopy,
+	"kCRT": KeyScreate,
+	"kDC": KeySdc,
+	"kDL": KeySdl,
+	"kslt": KeySelect,
+	"kEND": KeySend,
+	"kEOL": KeySeol,
+	"kEXT": KeySexit,
+	"kFND": KeySfind,
+	"kHLP": KeyShelp,
+	"kHOM": KeyShome,
+	"kIC": KeySic,
+	"kLFT": KeySleft,
+	"kMSG": KeySmessage,
+	"kMOV": KeySmove,
+	"kNXT": KeySnext,
+	"kOPT": KeySoptions,
+	"kPRV": KeySprevious,
+	"kPRT": KeySprint,
+	"kRDO": KeySredo,
+	"kRPL": KeySreplace,
+	"kRIT": KeySright,
+	"kRES": KeySrsume,
+	"kSAV": KeySsave,
+	"kSPD": KeySsuspend,
+	"kUND": KeySundo,
+	"rfi": ReqForInput,
+	"kf11": KeyF11,
+	"kf12": KeyF12,
+	"kf13": KeyF13,
+	"kf14": KeyF14,
+	"kf15": KeyF15,
+	"kf16": KeyF16,
+	"kf17": KeyF17,
+	"kf18": KeyF18,
+	"kf19": KeyF19,
+	"kf20": KeyF20,
+	"kf21": KeyF21,
+	"kf22": KeyF22,
+	"kf23": KeyF23,
+	"kf24": KeyF24,
+	"kf25": KeyF25,
+	"kf26": KeyF26,
+	"kf27": KeyF27,
+	"kf28": KeyF28,
+	"kf29": KeyF29,
+	"kf30": KeyF30,
+	"kf31": KeyF31,
+	"kf32": KeyF32,
+	"kf33": KeyF33,
+	"kf34": KeyF34,
+	"kf35": KeyF35,
+	"kf36": KeyF36,
+	"kf37": KeyF37,
+	"kf38": KeyF38,
+	"kf39": KeyF39,
+	"kf40": KeyF40,
+	"kf41": KeyF41,
+	"kf42": KeyF42,
+	"kf43": KeyF43,
+	"kf44": KeyF44,
+	"kf45": KeyF45,
+	"kf46": KeyF46,
+	"kf47": KeyF47,
+	"kf48": KeyF48,
+	"kf49": KeyF49,
+	"kf50": KeyF50,
+	"kf51": KeyF51,
+	"kf52": KeyF52,
+	"kf53": KeyF53,
+	"kf54": KeyF54,
+	"kf55": KeyF55,
+	"kf56": KeyF56,
+	"kf57": KeyF57,
+	"kf58": KeyF58,
+	"kf59": KeyF59,
+	"kf60": KeyF60,
+	"kf61": KeyF61,
+	"kf62": KeyF62,
+	"kf63": KeyF63,
+	"el1": ClrBol,
+	"mgc": ClearMargins,
+	"smgl": SetLeftMargin,
+	"smgr": SetRightMargin,
+	"fln": LabelFormat,
+	"sclk": SetClock,
+	"dclk": DisplayClock,
+	"rmclk": RemoveClock,
+	"cwin": CreateWindow,
+	"wingo": GotoWindow,
+	"hup": Hangup,
+	"dial": DialPhone,
+	"qdial": QuickDial,
+	"tone": Tone,
+	"pulse": Pulse,
+	"hook": FlashHook,
+	"pause": FixedPause,
+	"wait": WaitTone,
+	"u0": User0,
+	"u1": User1,
+	"u2": User2,
+	"u3": User3,
+	"u4": User4,
+	"u5": User5,
+	"u6": User6,
+	"u7": User7,
+	"u8": User8,
+	"u9": User9,
+	"op": OrigPair,
+	"oc": OrigColors,
+	"initc": InitializeColor,
+	"initp": InitializePair,
+	"scp": SetColorPair,
+	"setf": SetForeground,
+	"setb": SetBackground,
+	"cpi": ChangeCharPitch,
+	"lpi": ChangeLinePitch,
+	"chr": ChangeResHorz,
+	"cvr": ChangeResVert,
+	"defc": DefineChar,
+	"swidm": EnterDoublewideMode,
+	"sdrfq": EnterDraftQuality,
+	"sitm": EnterItalicsMode,
+	"slm": EnterLeftwardMode,
+	"smicm": EnterMicroMode,
+	"snlq": EnterNearLetterQuality,
+	"snrmq": EnterNormalQuality,
+	"sshm": EnterShadowMode,
+	"ssubm": EnterSubscriptMode,
+	"ssupm": EnterSuperscriptMode,
+	"sum": EnterUpwardMode,
+	"rwidm": ExitDoublewideMode,
+	"ritm": ExitItalicsMode,
+	"rlm": ExitLeftwardMode,
+	"rmicm": ExitMicroMode,
+	"rshm": ExitShadowMode,
+	"rsubm": ExitSubscriptMode,
+	"rsupm": ExitSuperscriptMode,
+	"rum": ExitUpwardMode,
+	"mhpa": MicroColumnAddress,
+	"mcud1": MicroDown,
+	"mcub1": MicroLeft,
+	"mcuf1": MicroRight,
+	"mvpa": MicroRowAddress,
+	"mcuu1": MicroUp,
+	"porder": OrderOfPins,
+	"mcud": ParmDownMicro,
+	"mcub": ParmLeftMicro,
+	"mcuf": ParmRightMicro,
+	"mcuu": ParmUpMicro,
+	"scs": SelectCharSet,
+	"smgb": SetBottomMargin,
+	"smgbp": SetBottomMarginParm,
+	"smglp": SetLeftMarginParm,
+	"smgrp": SetRightMarginParm,
+	"smgt": SetTopMargin,
+	"smgtp": SetTopMarginParm,
+	"sbim": StartBitImage,
+	"scsd": StartCharSetDef,
+	"rbim": StopBitImage,
+	"rcsd": StopCharSetDef,
+	"subcs": SubscriptCharacters,
+	"supcs": SuperscriptCharacters,
+	"docr": TheseCauseCr,
+	"zerom": ZeroMotion,
+	"csnm": CharSetNames,
+	"kmous": KeyMouse,
+	"minfo": MouseInfo,
+	"reqmp": ReqMousePos,
+	"getm": GetMouse,
+	"setaf": SetAForeground,
+	"setab": SetABackground,
+	"pfxl": PkeyPlab,
+	"devt": DeviceType,
+	"csin": CodeSetInit,
+	"s0ds": Set0DesSeq,
+	"s1ds": Set1DesSeq,
+	"s2ds": Set2DesSeq,
+	"s3ds": Set3DesSeq,
+	"smglr": SetLrMargin,
+	"smgtb": SetTbMargin,
+	"birep": BitImageRepeat,
+	"binel": BitImageNewline,
+	"bicr": BitImageCarriageReturn,
+	"colornm": ColorNames,
+	"defbi": DefineBitImageRegion,
+	"endbi": EndBitImageRegion,
+	"setcolor": SetColorBand,
+	"slines": SetPageLength,
+	"dispc": DisplayPcChar,
+	"smpch": EnterPcCharsetMode,
+	"rmpch": ExitPcCharsetMode,
+	"smsc": EnterScancodeMode,
+	"rmsc": ExitScancodeMode,
+	"pctrm": PcTermOptions,
+	"scesc": ScancodeEscape,
+	"scesa": AltScancodeEsc,
+	"ehhlm": EnterHorizontalHlMode,
+	"elhlm": EnterLeftHlMode,
+	"elohlm": EnterLowHlMode,
+	"erhlm": EnterRightHlMode,
+	"ethlm": EnterTopHlMode,
+	"evhlm": EnterVerticalHlMode,
+	"sgr1": SetAAttributes,
+	"slength": SetPglenInch,
+	"OTi2": TermcapInit2,
+	"OTrs": TermcapReset,
+	"OTnl": LinefeedIfNotLf,
+	"OTbc": BackspaceIfNotBs,
+	"OTko": OtherNonFunctionKeys,
+	"OTma": ArrowKeyMap,
+	"OTG2": AcsUlcorner,
+	"OTG3": AcsLlcorner,
+	"OTG1": AcsUrcorner,
+	"OTG4": AcsLrcorner,
+	"OTGR": AcsLtee,
+	"OTGL": AcsRtee,
+	"OTGU": AcsBtee,
+	"OTGD": AcsTtee,
+	"OTGH": AcsHline,
+	"OTGV": AcsVline,
+	"OTGC": AcsPlus,
+	"meml": MemoryLock,
+	"memu": MemoryUnlock,
+	"box1": BoxChars1,
+}
+var stringsByTermcapName = map[string]StringIndex{
+	"bt": BackTab,
+	"bl": Bell,
+	"cr": CarriageReturn,
+	"cs": ChangeScrollRegion,
+	"ct": ClearAllTabs,
+	"cl": ClearScreen,
+	"ce": ClrEol,
+	"cd": ClrEos,
+	"ch": ColumnAddress,
+	"CC": CommandCharacter,
+	"cm": CursorAddress,
+	"do": CursorDown,
+	"ho": CursorHome,
+	"vi": CursorInvisible,
+	"le": CursorLeft,
+	"CM": CursorMemAddress,
+	"ve": CursorNormal,
+	"nd": CursorRight,
+	"ll": CursorToLl,
+	"up": CursorUp,
+	"vs": CursorVisible,
+	"dc": DeleteCharacter,
+	"dl": DeleteLine,
+	"ds": DisStatusLine,
+	"hd": DownHalfLine,
+	"as": EnterAltCharsetMode,
+	"mb": EnterBlinkMode,
+	"md": EnterBoldMode,
+	"ti": EnterCaMode,
+	"dm": EnterDeleteMode,
+	"mh": EnterDimMode,
+	"im": EnterInsertMode,
+	"mk": EnterSecureMode,
+	"mp": EnterProtectedMode,
+	"mr": EnterReverseMode,
+	"so": EnterStandoutMode,
+	"us": EnterUnderlineMode,
+	"ec": EraseChars,
+	"ae": ExitAltCharsetMode,
+	"me": ExitAttributeMode,
+	"te": ExitCaMode,
+	"ed": ExitDeleteMode,
+	"ei": ExitInsertMode,
+	"se": ExitStandoutMode,
+	"ue": ExitUnderlineMode,
+	"vb": FlashScreen,
+	"ff": FormFeed,
+	"fs": FromStatusLine,
+	"i1": Init1string,
+	"is": Init2string,
+	"i3": Init3string,
+	"if": InitFile,
+	"ic": InsertCharacter,
+	"al": InsertLine,
+	"ip": InsertPadding,
+	"kb": KeyBackspace,
+	"ka": KeyCatab,
+	"kC": KeyClear,
+	"kt": KeyCtab,
+	"kD": KeyDc,
+	"kL": KeyDl,
+	"kd": KeyDown,
+	"kM": KeyEic,
+	"kE": KeyEol,
+	"kS": KeyEos,
+	"k0": KeyF0,
+	"k1": KeyF1,
+	"k;": KeyF10,
+	"k2": KeyF2,
+	"k3": KeyF3,
+	"k4": KeyF4,
+	"k5": KeyF5,
+	"k6": KeyF6,
+	"k7": KeyF7,
+	"k8": KeyF8,
+	"k9": KeyF9,
+	"kh": KeyHome,
+	"kI": KeyIc,
+	"kA": KeyIl,
+	"kl": KeyLeft,
+	"kH": KeyLl,
+	"kN": KeyNpage,
+	"kP": KeyPpage,
+	"kr": KeyRight,
+	"kF": KeySf,
+	"kR": KeySr,
+	"kT": KeyStab,
+	"ku": KeyUp,
+	"ke": KeypadLocal,
+	"ks": KeypadXmit,
+	"l0": LabF0,
+	"l1": LabF1,
+	"la": LabF10,
+	"l2": LabF2,
+	"l3": LabF3,
+	"l4": LabF4,
+	"l5": LabF5,
+	"l6": LabF6,
+	"l7": LabF7,
+	"l8": LabF8,
+	"l9": LabF9,
+	"mo": MetaOff,
+	"mm": MetaOn,
+	"nw": Newline,
+	"pc": PadChar,
+	"DC": ParmDch,
+	"DL": ParmDeleteLine,
+	"DO": ParmDownCursor,
+	"IC": ParmIch,
+	"SF": ParmIndex,
+	"AL": ParmInsertLine,
+	"LE": ParmLeftCursor,
+	"RI": ParmRightCursor,
+	"SR": ParmRindex,
+	"UP": ParmUpCursor,
+	"pk": PkeyKey,
+	"pl": PkeyLocal,
+	"px": PkeyXmit,
+	"ps": PrintScreen,
+	"pf": PrtrOff,
+	"po": PrtrOn,
+	"rp": RepeatChar,
+	"r1": Reset1string,
+	"r2": Reset2string,
+	"r3": Reset3string,
+	"rf": ResetFile,
+	"rc": RestoreCursor,
+	"cv": RowAddress,
+	"sc": SaveCursor,
+	"sf": ScrollForward,
+	"sr": ScrollReverse,
+	"sa": SetAttributes,
+	"st": SetTab,
+	"wi": SetWindow,
+	"ta": Tab,
+	"ts": ToStatusLine,
+	"uc": UnderlineChar,
+	"hu": UpHalfLine,
+	"iP": InitProg,
+	"K1": KeyA1,
+	"K3": KeyA3,
+	"K2": KeyB2,
+	"K4": KeyC1,
+	"K5": KeyC3,
+	"pO": PrtrNon,
+	"rP": CharPadding,
+	"ac": AcsChars,
+	"pn": PlabNorm,
+	"kB": KeyBtab,
+	"SX": EnterXonMode,
+	"RX": ExitXonMode,
+	"SA": EnterAmMode,
+	"RA": ExitAmMode,
+	"XN": XonCharacter,
+	"XF": XoffCharacter,
+	"eA": EnaAcs,
+	"LO": LabelOn,
+	"LF": LabelOff,
+	"@1": KeyBeg,
+	"@2": KeyCancel,
+	"@3": KeyClose,
+	"@4": KeyCommand,
+	"@5": KeyCopy,
+	"@6": KeyCreate,
+	"@7": KeyEnd,
+	"@8": KeyEnter,
+	"@9": KeyExit,
+	"@0": KeyFind,
+	"%1": KeyHelp,
+	"%2": KeyMark,
+	"%3": KeyMessage,
+	"%4": KeyMove,
+	"%5": KeyNext,
+	"%6": KeyOpen,
+	"%7": KeyOptions,
+	"%8": KeyPrevious,
+	"%9": KeyPrint,
+	"%0": KeyRedo,
+	"&1": KeyReference,
+	"&2": KeyRefresh,
+	"&3": KeyReplace,
+	"&4": KeyRestart,
+	"&5": KeyResume,
+	"&6": KeySave,
+	"&7": KeySuspend,
+	"&8": KeyUndo,
+	"&9": KeySbeg,
+	"&0": KeyScancel,
+	"*1": KeyScommand,
+	"*2": KeyScopy,
+	"*3": KeyScreate,
+	"*4": KeySdc,
+	"*5": KeySdl,
+	"*6": KeySelect,
+	"*7": KeySend,
+	"*8": KeySeol,
+	"*9": KeySexit,
+	"*0": KeySfind,
+	"#1": KeyShelp,
+	"#2": KeyShome,
+	"#3": KeySic,
+	"#4": KeySleft,
+	"%a": KeySmessage,
+	"%b": KeySmove,
+	"%c": KeySnext,
+	"%d": KeySoptions,
+	"%e": KeySprevious,
+	"%f": KeySprint,
+	"%g": KeySredo,
+	"%h": KeySreplace,
+	"%i": KeySright,
+	"%j": KeySrsume,
+	"!1": KeySsave,
+	"!2": KeySsuspend,
+	"!3": KeySundo,
+	"RF": ReqForInput,
+	"F1": KeyF11,
+	"F2": KeyF12,
+	"F3": KeyF13,
+	"F4": KeyF14,
+	"F5": KeyF15,
+	"F6": KeyF16,
+	"F7": KeyF17,
+	"F8": KeyF18,
+	"F9": KeyF19,
+	"FA": KeyF20,
+	"FB": KeyF21,
+	"FC": KeyF22,
+	"FD": KeyF23,
+	"FE": KeyF24,
+	"FF": KeyF25,
+	"FG": KeyF26,
+	"FH": KeyF27,
+	"FI": KeyF28,
+	"FJ": KeyF29,
+	"FK": KeyF30,
+	"FL": KeyF31,
+	"FM": KeyF32,
+	"FN": KeyF33,
+	"FO": KeyF34,
+	"FP": KeyF35,
+	"FQ": KeyF36,
+	"FR": KeyF37,
+	"FS": KeyF38,
+	"FT": KeyF39,
+	"FU": KeyF40,
+	"FV": KeyF41,
+	"FW": KeyF42,
+	"FX": KeyF43,
+	"FY": KeyF44,
+	"FZ": KeyF45,
+	"Fa": KeyF46,
+	"Fb": KeyF47,
+	"Fc": KeyF48,
+	"Fd": KeyF49,
+	"Fe": KeyF50,
+	"Ff": KeyF51,
+	"Fg": KeyF52,
+	"Fh": KeyF53,
+	"Fi": KeyF54,
+	"Fj": KeyF55,
+	"Fk": KeyF56,
+	"Fl": KeyF57,
+	"Fm": KeyF58,
+	"Fn": KeyF59,
+	"Fo": KeyF60,
+	"Fp": KeyF61,
+	"Fq": KeyF62,
+	"Fr": KeyF63,
+	"cb": ClrBol,
+	"MC": ClearMargins,
+	"ML": SetLeftMargin,
+	"MR": SetRightMargin,
+	"Lf": LabelFormat,
+	"SC": SetClock,
+	"DK": DisplayClock,
+	"RC": RemoveClock,
+	"CW": CreateWindow,
+	"WG": GotoWindow,
+	"HU": Hangup,
+	"DI": DialPhone,
+	"QD": QuickDial,
+	"TO": Tone,
+	"PU": Pulse,
+	"fh": FlashHook,
+	"PA": FixedPause,
+	"WA": WaitTone,
+	"u0": User0,
+	"u1": User1,
+	"u2": User2,
+	"u3": User3,
+	"u4": User4,
+	"u5": User5,
+	"u6": User6,
+	"u7": User7,
+	"u8": User8,
+	"u9": User9,
+	"op": OrigPair,
+	"oc": OrigColors,
+	"Ic": InitializeColor,
+	"Ip": InitializePair,
+	"sp": SetColorPair,
+	"Sf": SetForeground,
+	"Sb": SetBackground,
+	"ZA": ChangeCharPitch,
+	"ZB": ChangeLinePitch,
+	"ZC": ChangeResHorz,
+	"ZD": ChangeResVert,
+	"ZE": DefineChar,
+	"ZF": EnterDoublewideMode,
+	"ZG": EnterDraftQuality,
+	"ZH": EnterItalicsMode,
+	"ZI": EnterLeftwardMode,
+	"ZJ": EnterMicroMode,
+	"ZK": EnterNearLetterQuality,
+	"ZL": EnterNormalQuality,
+	"ZM": EnterShadowMode,
+	"ZN": EnterSubscriptMode,
+	"ZO": EnterSuperscriptMode,
+	"ZP": EnterUpwardMode,
+	"ZQ": ExitDoublewideMode,
+	"ZR": ExitItalicsMode,
+	"ZS": ExitLeftwardMode,
+	"ZT": ExitMicroMode,
+	"ZU": ExitShadowMode,
+	"ZV": ExitSubscriptMode,
+	"ZW": ExitSuperscriptMode,
+	"ZX": ExitUpwardMode,
+	"ZY": MicroColumnAddress,
+	"ZZ": MicroDown,
+	"Za": MicroLeft,
+	"Zb": MicroRight,
+	"Zc": MicroRowAddress,
+	"Zd": MicroUp,
+	"Ze": OrderOfPins,
+	"Zf": ParmDownMicro,
+	"Zg": ParmLeftMicro,
+	"Zh": ParmRightMicro,
+	"Zi": ParmUpMicro,
+	"Zj": SelectCharSet,
+	"Zk": SetBottomMargin,
+	"Zl": SetBottomMarginParm,
+	"Zm": SetLeftMarginParm,
+	"Zn": SetRightMarginParm,
+	"Zo": SetTopMargin,
+	"Zp": SetTopMarginParm,
+	"Zq": StartBitImage,
+	"Zr": StartCharSetDef,
+	"Zs": StopBitImage,
+	"Zt": StopCharSetDef,
+	"Zu": SubscriptCharacters,
+	"Zv": SuperscriptCharacters,
+	"Zw": TheseCauseCr,
+	"Zx": ZeroMotion,
+	"Zy": CharSetNames,
+	"Km": KeyMouse,
+	"Mi": MouseInfo,
+	"RQ": ReqMousePos,
+	"Gm": GetMouse,
+	"AF": SetAForeground,
+	"AB": SetABackground,
+	"xl": PkeyPlab,
+	"dv": DeviceType,
+	"ci": CodeSetInit,
+	"s0": Set0DesSeq,
+	"s1": Set1DesSeq,
+	"s2": Set2DesSeq,
+	"s3": Set3DesSeq,
+	"MT": SetTbMargin,
+	"Xy": BitImageRepeat,
+	"Zz": BitImageNewline,
+	"Yv": BitImageCarriageReturn,
+	"Yw": ColorNames,
+	"Yx": DefineBitImageRegion,
+	"Yy": EndBitImageRegion,
+	"Yz": SetColorBand,
+	"YZ": SetPageLength,
+	"S1": DisplayPcChar,
+	"S2": EnterPcCharsetMode,
+	"S3": ExitPcCharsetMode,
+	"S4": EnterScancodeMode,
+	"S5": ExitScancodeMode,
+	"S6": PcTermOptions,
+	"S7": ScancodeEscape,
+	"S8": AltScancodeEsc,
+	"Xh": EnterHorizontalHlMode,
+	"Xl": EnterLeftHlMode,
+	"Xo": EnterLowHlMode,
+	"Xr": EnterRightHlMode,
+	"Xt": EnterTopHlMode,
+	"Xv": EnterVerticalHlMode,
+	"sA": SetAAttributes,
+	"YI": SetPglenInch,
+	"i2": TermcapInit2,
+	"rs": TermcapReset,
+	"nl": LinefeedIfNotLf,
+	"bc": BackspaceIfNotBs,
+	"ko": OtherNonFunctionKeys,
+	"ma": ArrowKeyMap,
+	"G2": AcsUlcorner,
+	"G3": AcsLlcorner,
+	"G1": AcsUrcorner,
+	"G4": AcsLrcorner,
+	"GR": AcsLtee,
+	"GL": AcsRtee,
+	"GU": AcsBtee,
+	"GD": AcsTtee,
+	"GH": AcsHline,
+	"GV": AcsVline,
+	"GC": AcsPlus,
+	"ml": MemoryLock,
+	"mu": MemoryUnlock,
+	"bx": BoxChars1,
+}
+
+// CapName returns the capability's short name, as used in terminfo
+// source, e.g. "cup" for CursorAddress.
+func (i StringIndex) CapName() string {
+	if i < 0 || i > MaxStringIndex {
+		return ""
+	}
+	return stringCapNames[i]
+}
+
+// TermcapName returns the capability's termcap code, e.g. "cm" for
+// CursorAddress, or "" if it doesn't have one.
+func (i StringIndex) TermcapName() string {
+	if i < 0 || i > MaxStringIndex {
+		return ""
+	}
+	return stringTermcapNames[i]
+}
+
+// LookupString returns the index of the string capability with the
+// given capname, e.g. CursorAddress for "cup".
+func LookupString(capname string) (StringIndex, bool) {
+	i, ok := stringsByCapName[capname]
+	return i, ok
+}
+
+// LookupTermcapString returns the index of the string capability with
+// the given termcap code, e.g. CursorAddress for "cm".
+func LookupTermcapString(code string) (StringIndex, bool) {
+	i, ok := stringsByTermcapName[code]
+	return i, ok
+}
 type NumberIndex int
 const (
 	Columns NumberIndex = iota
@@ -463,6 +2158,202 @@ const (
 	NumberOfFunctionKeys
 	MaxNumberIndex = NumberOfFunctionKeys
 )
+var numberCapNames = [...]string{
+	Columns: "cols",
+	InitTabs: "it",
+	Lines: "lines",
+	LinesOfMemory: "lm",
+	MagicCookieGlitch: "xmc",
+	PaddingBaudRate: "pb",
+	VirtualTerminal: "vt",
+	WidthStatusLine: "wsl",
+	NumLabels: "nlab",
+	LabelHeight: "lh",
+	LabelWidth: "lw",
+	MaxAttributes: "ma",
+	MaximumWindows: "wnum",
+	MaxColors: "colors",
+	MaxPairs: "pairs",
+	NoColorVideo: "ncv",
+	BufferCapacity: "bufsz",
+	DotVertSpacing: "spinv",
+	DotHorzSpacing: "spinh",
+	MaxMicroAddress: "maddr",
+	MaxMicroJump: "mjump",
+	MicroColSize: "mcs",
+	MicroLineSize: "mls",
+	NumberOfPins: "npins",
+	OutputResChar: "orc",
+	OutputResLine: "orl",
+	OutputResHorzInch: "orhi",
+	OutputResVertInch: "orvi",
+	PrintRate: "cps",
+	WideCharSize: "widcs",
+	Buttons: "btns",
+	BitImageEntwining: "bitwin",
+	BitImageType: "bitype",
+	MagicCookieGlitchUl: "OTug",
+	CarriageReturnDelay: "OTdC",
+	NewLineDelay: "OTdN",
+	BackspaceDelay: "OTdB",
+	HorizontalTabDelay: "OTdT",
+	NumberOfFunctionKeys: "OTkn",
+}
+var numberTermcapNames = [...]string{
+	Columns: "co",
+	InitTabs: "it",
+	Lines: "li",
+	LinesOfMemory: "lm",
+	MagicCookieGlitch: "sg",
+	PaddingBaudRate: "pb",
+	VirtualTerminal: "vt",
+	WidthStatusLine: "ws",
+	NumLabels: "Nl",
+	LabelHeight: "lh",
+	LabelWidth: "lw",
+	MaxAttributes: "ma",
+	MaximumWindows: "MW",
+	MaxColors: "Co",
+	MaxPairs: "pa",
+	NoColorVideo: "NC",
+	BufferCapacity: "Ya",
+	DotVertSpacing: "Yb",
+	DotHorzSpacing: "Yc",
+	MaxMicroAddress: "Yd",
+	MaxMicroJump: "Ye",
+	MicroColSize: "Yf",
+	MicroLineSize: "Yg",
+	NumberOfPins: "Yh",
+	OutputResChar: "Yi",
+	OutputResLine: "Yj",
+	OutputResHorzInch: "Yk",
+	OutputResVertInch: "Yl",
+	PrintRate: "Ym",
+	WideCharSize: "Yn",
+	Buttons: "BT",
+	BitImageEntwining: "Yo",
+	BitImageType: "Yp",
+	MagicCookieGlitchUl: "ug",
+	CarriageReturnDelay: "dC",
+	NewLineDelay: "dN",
+	BackspaceDelay: "dB",
+	HorizontalTabDelay: "dT",
+	NumberOfFunctionKeys: "kn",
+}
+var numbersByCapName = map[string]NumberIndex{
+	"cols": Columns,
+	"it": InitTabs,
+	"lines": Lines,
+	"lm": LinesOfMemory,
+	"xmc": MagicCookieGlitch,
+	"pb": PaddingBaudRate,
+	"vt": VirtualTerminal,
+	"wsl": WidthStatusLine,
+	"nlab": NumLabels,
+	"lh": LabelHeight,
+	"lw": LabelWidth,
+	"ma": MaxAttributes,
+	"wnum": MaximumWindows,
+	"colors": MaxColors,
+	"pairs": MaxPairs,
+	"ncv": NoColorVideo,
+	"bufsz": BufferCapacity,
+	"spinv": DotVertSpacing,
+	"spinh": DotHorzSpacing,
+	"maddr": MaxMicroAddress,
+	"mjump": MaxMicroJump,
+	"mcs": MicroColSize,
+	"mls": MicroLineSize,
+	"npins": NumberOfPins,
+	"orc": OutputResChar,
+	"orl": OutputResLine,
+	"orhi": OutputResHorzInch,
+	"orvi": OutputResVertInch,
+	"cps": PrintRate,
+	"widcs": WideCharSize,
+	"btns": Buttons,
+	"bitwin": BitImageEntwining,
+	"bitype": BitImageType,
+	"OTug": MagicCookieGlitchUl,
+	"OTdC": CarriageReturnDelay,
+	"OTdN": NewLineDelay,
+	"OTdB": BackspaceDelay,
+	"OTdT": HorizontalTabDelay,
+	"OTkn": NumberOfFunctionKeys,
+}
+var numbersByTermcapName = map[string]NumberIndex{
+	"co": Columns,
+	"it": InitTabs,
+	"li": Lines,
+	"lm": LinesOfMemory,
+	"sg": MagicCookieGlitch,
+	"pb": PaddingBaudRate,
+	"vt": VirtualTerminal,
+	"ws": WidthStatusLine,
+	"Nl": NumLabels,
+	"lh": LabelHeight,
+	"lw": LabelWidth,
+	"ma": MaxAttributes,
+	"MW": MaximumWindows,
+	"Co": MaxColors,
+	"pa": MaxPairs,
+	"NC": NoColorVideo,
+	"Ya": BufferCapacity,
+	"Yb": DotVertSpacing,
+	"Yc": DotHorzSpacing,
+	"Yd": MaxMicroAddress,
+	"Ye": MaxMicroJump,
+	"Yf": MicroColSize,
+	"Yg": MicroLineSize,
+	"Yh": NumberOfPins,
+	"Yi": OutputResChar,
+	"Yj": OutputResLine,
+	"Yk": OutputResHorzInch,
+	"Yl": OutputResVertInch,
+	"Ym": PrintRate,
+	"Yn": WideCharSize,
+	"BT": Buttons,
+	"Yo": BitImageEntwining,
+	"Yp": BitImageType,
+	"ug": MagicCookieGlitchUl,
+	"dC": CarriageReturnDelay,
+	"dN": NewLineDelay,
+	"dB": BackspaceDelay,
+	"dT": HorizontalTabDelay,
+	"kn": NumberOfFunctionKeys,
+}
+
+// CapName returns the capability's short name, as used in terminfo
+// source, e.g. "cols" for Columns.
+func (i NumberIndex) CapName() string {
+	if i < 0 || i > MaxNumberIndex {
+		return ""
+	}
+	return numberCapNames[i]
+}
+
+// TermcapName returns the capability's termcap code, e.g. "co" for
+// Columns, or "" if it doesn't have one.
+func (i NumberIndex) TermcapName() string {
+	if i < 0 || i > MaxNumberIndex {
+		return ""
+	}
+	return numberTermcapNames[i]
+}
+
+// LookupNumber returns the index of the number capability with the
+// given capname, e.g. Columns for "cols".
+func LookupNumber(capname string) (NumberIndex, bool) {
+	i, ok := numbersByCapName[capname]
+	return i, ok
+}
+
+// LookupTermcapNumber returns the index of the number capability with
+// the given termcap code, e.g. Columns for "co".
+func LookupTermcapNumber(code string) (NumberIndex, bool) {
+	i, ok := numbersByTermcapName[code]
+	return i, ok
+}
 type BooleanIndex int
 const (
 	AutoLeftMargin BooleanIndex = iota
@@ -511,3 +2402,219 @@ const (
 	ReturnDoesClrEol
 	MaxBooleanIndex = ReturnDoesClrEol
 )
+var booleanCapNames = [...]string{
+	AutoLeftMargin: "bw",
+	AutoRightMargin: "am",
+	NoEscCtlc: "xsb",
+	CeolStandoutGlitch: "xhp",
+	EatNewlineGlitch: "xenl",
+	EraseOverstrike: "eo",
+	GenericType: "gn",
+	HardCopy: "hc",
+	HasMetaKey: "km",
+	HasStatusLine: "hs",
+	InsertNullGlitch: "in",
+	MemoryAbove: "da",
+	MemoryBelow: "db",
+	MoveInsertMode: "mir",
+	MoveStandoutMode: "msgr",
+	OverStrike: "os",
+	StatusLineEscOk: "eslok",
+	DestTabsMagicSmso: "xt",
+	TildeGlitch: "hz",
+	TransparentUnderline: "ul",
+	XonXoff: "xon",
+	NeedsXonXoff: "nxon",
+	PrtrSilent: "mc5i",
+	HardCursor: "chts",
+	NonRevRmcup: "nrrmc",
+	NoPadChar: "npc",
+	NonDestScrollRegion: "ndscr",
+	CanChange: "ccc",
+	BackColorErase: "bce",
+	HueLightnessSaturation: "hls",
+	ColAddrGlitch: "xhpa",
+	CrCancelsMicroMode: "crxm",
+	HasPrintWheel: "daisy",
+	RowAddrGlitch: "xvpa",
+	SemiAutoRightMargin: "sam",
+	CpiChangesRes: "cpix",
+	LpiChangesRes: "lpix",
+	BackspacesWithBs: "OTbs",
+	CrtNoScrolling: "OTns",
+	NoCorrectlyWorkingCr: "OTnc",
+	GnuHasMetaKey: "OTMT",
+	LinefeedIsNewline: "OTNL",
+	HasHardwareTabs: "OTpt",
+	ReturnDoesClrEol: "OTxr",
+}
+var booleanTermcapNames = [...]string{
+	AutoLeftMargin: "bw",
+	AutoRightMargin: "am",
+	NoEscCtlc: "xb",
+	CeolStandoutGlitch: "xs",
+	EatNewlineGlitch: "xn",
+	EraseOverstrike: "eo",
+	GenericType: "gn",
+	HardCopy: "hc",
+	HasMetaKey: "km",
+	HasStatusLine: "hs",
+	InsertNullGlitch: "in",
+	MemoryAbove: "da",
+	MemoryBelow: "db",
+	MoveInsertMode: "mi",
+	MoveStandoutMode: "ms",
+	OverStrike: "os",
+	StatusLineEscOk: "es",
+	DestTabsMagicSmso: "xt",
+	TildeGlitch: "hz",
+	TransparentUnderline: "ul",
+	XonXoff: "xo",
+	NeedsXonXoff: "nx",
+	PrtrSilent: "5i",
+	HardCursor: "HC",
+	NonRevRmcup: "NR",
+	NoPadChar: "NP",
+	NonDestScrollRegion: "ND",
+	CanChange: "cc",
+	BackColorErase: "ut",
+	HueLightnessSaturation: "hl",
+	ColAddrGlitch: "YA",
+	CrCancelsMicroMode: "YB",
+	HasPrintWheel: "YC",
+	RowAddrGlitch: "YD",
+	SemiAutoRightMargin: "YE",
+	CpiChangesRes: "YF",
+	LpiChangesRes: "YG",
+	BackspacesWithBs: "bs",
+	CrtNoScrolling: "ns",
+	NoCorrectlyWorkingCr: "nc",
+	GnuHasMetaKey: "MT",
+	LinefeedIsNewline: "NL",
+	HasHardwareTabs: "pt",
+	ReturnDoesClrEol: "xr",
+}
+var booleansByCapName = map[string]BooleanIndex{
+	"bw": AutoLeftMargin,
+	"am": AutoRightMargin,
+	"xsb": NoEscCtlc,
+	"xhp": CeolStandoutGlitch,
+	"xenl": EatNewlineGlitch,
+	"eo": EraseOverstrike,
+	"gn": GenericType,
+	"hc": HardCopy,
+	"km": HasMetaKey,
+	"hs": HasStatusLine,
+	"in": InsertNullGlitch,
+	"da": MemoryAbove,
+	"db": MemoryBelow,
+	"mir": MoveInsertMode,
+	"msgr": MoveStandoutMode,
+	"os": OverStrike,
+	"eslok": StatusLineEscOk,
+	"xt": DestTabsMagicSmso,
+	"hz": TildeGlitch,
+	"ul": TransparentUnderline,
+	"xon": XonXoff,
+	"nxon": NeedsXonXoff,
+	"mc5i": PrtrSilent,
+	"chts": HardCursor,
+	"nrrmc": NonRevRmcup,
+	"npc": NoPadChar,
+	"ndscr": NonDestScrollRegion,
+	"ccc": CanChange,
+	"bce": BackColorErase,
+	"hls": HueLightnessSaturation,
+	"xhpa": ColAddrGlitch,
+	"crxm": CrCancelsMicroMode,
+	"daisy": HasPrintWheel,
+	"xvpa": RowAddrGlitch,
+	"sam": SemiAutoRightMargin,
+	"cpix": CpiChangesRes,
+	"lpix": LpiChangesRes,
+	"OTbs": BackspacesWithBs,
+	"OTns": CrtNoScrolling,
+	"OTnc": NoCorrectlyWorkingCr,
+	"OTMT": GnuHasMetaKey,
+	"OTNL": LinefeedIsNewline,
+	"OTpt": HasHardwareTabs,
+	"OTxr": ReturnDoesClrEol,
+}
+var booleansByTermcapName = map[string]BooleanIndex{
+	"bw": AutoLeftMargin,
+	"am": AutoRightMargin,
+	"xb": NoEscCtlc,
+	"xs": CeolStandoutGlitch,
+	"xn": EatNewlineGlitch,
+	"eo": EraseOverstrike,
+	"gn": GenericType,
+	"hc": HardCopy,
+	"km": HasMetaKey,
+	"hs": HasStatusLine,
+	"in": InsertNullGlitch,
+	"da": MemoryAbove,
+	"db": MemoryBelow,
+	"mi": MoveInsertMode,
+	"ms": MoveStandoutMode,
+	"os": OverStrike,
+	"es": StatusLineEscOk,
+	"xt": DestTabsMagicSmso,
+	"hz": TildeGlitch,
+	"ul": TransparentUnderline,
+	"xo": XonXoff,
+	"nx": NeedsXonXoff,
+	"5i": PrtrSilent,
+	"HC": HardCursor,
+	"NR": NonRevRmcup,
+	"NP": NoPadChar,
+	"ND": NonDestScrollRegion,
+	"cc": CanChange,
+	"ut": BackColorErase,
+	"hl": HueLightnessSaturation,
+	"YA": ColAddrGlitch,
+	"YB": CrCancelsMicroMode,
+	"YC": HasPrintWheel,
+	"YD": RowAddrGlitch,
+	"YE": SemiAutoRightMargin,
+	"YF": CpiChangesRes,
+	"YG": LpiChangesRes,
+	"bs": BackspacesWithBs,
+	"ns": CrtNoScrolling,
+	"nc": NoCorrectlyWorkingCr,
+	"MT": GnuHasMetaKey,
+	"NL": LinefeedIsNewline,
+	"pt": HasHardwareTabs,
+	"xr": ReturnDoesClrEol,
+}
+
+// CapName returns the capability's short name, as used in terminfo
+// source, e.g. "am" for AutoRightMargin.
+func (i BooleanIndex) CapName() string {
+	if i < 0 || i > MaxBooleanIndex {
+		return ""
+	}
+	return booleanCapNames[i]
+}
+
+// TermcapName returns the capability's termcap code, e.g. "am" for
+// AutoRightMargin, or "" if it doesn't have one.
+func (i BooleanIndex) TermcapName() string {
+	if i < 0 || i > MaxBooleanIndex {
+		return ""
+	}
+	return booleanTermcapNames[i]
+}
+
+// LookupBoolean returns the index of the boolean capability with the
+// given capname, e.g. AutoRightMargin for "am".
+func LookupBoolean(capname string) (BooleanIndex, bool) {
+	i, ok := booleansByCapName[capname]
+	return i, ok
+}
+
+// LookupTermcapBoolean returns the index of the boolean capability with
+// the given termcap code, e.g. AutoRightMargin for "am".
+func LookupTermcapBoolean(code string) (BooleanIndex, bool) {
+	i, ok := booleansByTermcapName[code]
+	return i, ok
+}
@@ -1,28 +1,25 @@
 // package terminfo is a pure-go library for reading compiled term
 // files as described in term(5).
 //
-// Only directory-tree descriptions are supported right now (patches welcome!).
+// Both directory-tree and hashed (Berkeley DB 1.85) databases are
+// supported.
 package terminfo // import "gopkg.in/terminfo.v0"
 
 //go:generate find . -name termh*.go -delete
-//go:generate ./gen-termh.py
+//go:generate ./gen-termh.py $NCURSES_CAPS
 //go:generate stringer -type BooleanIndex,NumberIndex,StringIndex -output termh_string.go
 
 import (
+	"bytes"
 	"encoding/binary"
 	"errors"
 	"fmt"
-	"image/color"
 	"io"
 	"os"
 	"os/user"
 	"path/filepath"
-	"regexp"
-	"strconv"
 	"strings"
-	"time"
-
-	"gopkg.in/termios.v0"
+	"sync"
 )
 
 // ComipledInLocations is the list of “compiled-in” locations that
@@ -30,6 +27,20 @@ import (
 // adjust as necessary before calling anything else.
 var CompiledInLocations = []string{"/etc/terminfo", "/lib/terminfo", "/usr/share/terminfo"}
 
+// A Fallback returns the compiled entry (as read by Unmarshal) for the
+// named terminal, if it has one.
+type Fallback func(term string) ([]byte, bool)
+
+var fallbacks []Fallback
+
+// RegisterFallback adds f to the places to look for a terminal's
+// description in, after all the ones in the search path. Fallbacks are
+// tried in the order they were registered in, so this is meant to be
+// called from init functions, such as the builtin subpackage's.
+func RegisterFallback(f Fallback) {
+	fallbacks = append(fallbacks, f)
+}
+
 func home() string {
 	if h := os.Getenv("HOME"); h != "" {
 		return h
@@ -41,196 +52,33 @@ func home() string {
 	return "/"
 }
 
-type paramStack struct {
-	stack []interface{}
+func UnescapeString(tpl string, args ...interface{}) ([]byte, error) {
+	return Unescape([]byte(tpl), args...)
 }
 
-func (p *paramStack) push(arg interface{}) {
-	switch arg.(type) {
-	case string, int:
-		// ok
-	default:
-		panic(fmt.Errorf("unexpected type %T in stack.push", arg))
+// Unescape evaluates the parametrized string tpl with the given
+// args. Static variables only last for the duration of the call; use
+// TermInfo.Unescape to have them persist across calls.
+func Unescape(tpl []byte, args ...interface{}) ([]byte, error) {
+	p, err := Compile(tpl)
+	if err != nil {
+		return nil, err
 	}
-	p.stack = append(p.stack, arg)
+	return p.Execute(args...)
 }
 
-func (p *paramStack) pushBool(arg bool) {
-	if arg {
-		p.push(1)
-	} else {
-		p.push(0)
+// TParm evaluates the parametrized string tpl with the given args, the
+// way ncurses' tparm does; see Program.TParm.
+func TParm(tpl []byte, args ...interface{}) ([]byte, error) {
+	p, err := Compile(tpl)
+	if err != nil {
+		return nil, err
 	}
+	return p.TParm(args...)
 }
 
-func (p *paramStack) pop() interface{} {
-	x := p.stack[len(p.stack)-1]
-	p.stack = p.stack[:len(p.stack)-1]
-	return x
-}
-
-func (p *paramStack) popInt() int {
-	return p.pop().(int)
-}
-
-func (p *paramStack) popBool() bool {
-	return p.popInt() != 0
-}
-
-func (p *paramStack) popString() string {
-	return p.pop().(string)
-}
-
-func UnescapeString(tpl string, args ...interface{}) ([]byte, error) {
-	return Unescape([]byte(tpl), args...)
-}
-func Unescape(tpl []byte, args ...interface{}) ([]byte, error) {
-	var buf []byte
-	var stack paramStack
-	isParam := false
-	for i := 0; i < len(tpl); i++ {
-		if !isParam {
-			if tpl[i] == '%' {
-				isParam = true
-			} else {
-				buf = append(buf, tpl[i])
-			}
-			continue
-		}
-		isParam = false
-		switch tpl[i] {
-		case '%':
-			buf = append(buf, '%')
-		case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9', ':', '#', ' ', 'd', 'o', 'x', 'X', 's', 'c':
-			done := false
-			var j int
-		printfLikeInner:
-			for j = i; j < len(tpl); j++ {
-				switch tpl[j] {
-				case 'd', 'o', 'x', 'X', 's', 'c':
-					done = true
-					break printfLikeInner
-				}
-			}
-			if !done {
-				return nil, ErrTruncatedParametrizedString
-			}
-			f := fmt.Sprintf("%%%s", tpl[i:j+1])
-			gen := fmt.Sprintf(f, stack.pop())
-			i = j
-			buf = append(buf, []byte(gen)...)
-		case 'p':
-			if len(tpl) <= i+1 {
-				return nil, ErrTruncatedParametrizedString
-			}
-			i++
-			n, err := strconv.Atoi(string(tpl[i : i+1]))
-			if err != nil {
-				return nil, err
-			}
-			if n > len(args) {
-				return nil, ErrMissingArgs
-			}
-			stack.push(args[n-1])
-		case 'P', 'g':
-			return nil, ErrNotImplemented
-		case '\'':
-			if len(tpl) <= i+2 {
-				return nil, ErrTruncatedParametrizedString
-			}
-			i++
-			stack.push(int(tpl[i]))
-			i++
-			if tpl[i] != '\'' {
-				return nil, ErrBadParametrizedString
-			}
-		case '{':
-			n := 0
-		literalNumberInner:
-			for j := i + 1; j < len(tpl); j++ {
-				switch tpl[j] {
-				case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
-					n = n*10 + int(tpl[j]-'0')
-				case '}':
-					i = j
-					break literalNumberInner
-				default:
-					return nil, ErrBadParametrizedString
-				}
-			}
-			stack.push(n)
-		case 'l':
-			stack.push(len(stack.popString()))
-		case '+':
-			stack.push(stack.popInt() + stack.popInt())
-		case '-':
-			stack.push(-stack.popInt() + stack.popInt())
-		case '*':
-			stack.push(stack.popInt() * stack.popInt())
-		case '/':
-			n1 := stack.popInt()
-			n2 := stack.popInt()
-			stack.push(n2 / n1)
-		case 'm':
-			n1 := stack.popInt()
-			n2 := stack.popInt()
-			stack.push(n2 % n1)
-		case '&':
-			stack.push(stack.popInt() & stack.popInt())
-		case '|':
-			stack.push(stack.popInt() | stack.popInt())
-		case '^':
-			stack.push(stack.popInt() ^ stack.popInt())
-		case '=':
-			stack.pushBool(stack.popInt() == stack.popInt())
-		case '<':
-			stack.pushBool(stack.popInt() > stack.popInt())
-		case '>':
-			stack.pushBool(stack.popInt() < stack.popInt())
-		case 'A':
-			stack.pushBool(stack.popBool() && stack.popBool())
-		case 'O':
-			stack.pushBool(stack.popBool() || stack.popBool())
-		case '!':
-			stack.pushBool(!stack.popBool())
-		case '~':
-			// TODO: find an example of this to check word size & etc
-			stack.push(^stack.popInt())
-		case 'i':
-			switch len(args) {
-			default:
-				args[1] = args[1].(int) + 1
-				fallthrough
-			case 1:
-				args[0] = args[0].(int) + 1
-			case 0:
-				// do naught
-			}
-		case '?', ';':
-			// return nil, ErrNotImplemented
-		case 't':
-			if stack.popBool() {
-				// test case was true; continue as usual
-				continue
-			}
-			// test case was false; look for %e or %;
-			fallthrough
-		case 'e':
-			elseOk := tpl[i] == 't'
-			ok := false
-			for i = i + 1; i < len(tpl)-1; i++ {
-				if tpl[i] == '%' && (tpl[i+1] == ';' || (elseOk && tpl[i+1] == 'e')) {
-					ok = true
-					i++
-					break
-				}
-			}
-			if !ok {
-				return nil, ErrBadParametrizedString
-			}
-		}
-	}
-	return buf, nil
+func TParmString(tpl string, args ...interface{}) ([]byte, error) {
+	return TParm([]byte(tpl), args...)
 }
 
 type TermInfo struct {
@@ -239,180 +87,216 @@ type TermInfo struct {
 	Numbers    []int16
 	BigNumbers []int32
 	Strings    map[StringIndex][]byte
-	tty        *os.File
+
+	// ncurses extended (user-defined) capabilities, by name.
+	ExtBooleans map[string]bool
+	ExtNumbers  map[string]int32
+	ExtStrings  map[string][]byte
+
+	// If TParm is set, Unescape evaluates strings as ncurses' tparm
+	// does (see Program.TParm), rather than reporting missing args and
+	// the like as errors.
+	TParm bool
+
+	// If Colors isn't zero, it's what ColorDepth returns (or 0, if it's
+	// negative), whatever the entry and the environment say.
+	Colors int
+
+	tty     *os.File
+	statics variables
+	pairs   colorPairs
+	// the speed of tty, read the first time it's needed
+	ttySpeedOnce sync.Once
+	ttySpeed     int
+
+	// the compiled Strings, by index
+	programsMu sync.Mutex
+	programs   map[StringIndex]*program
 }
 
-var findPadIndexes = regexp.MustCompile(`\$<(\d+)(\*)?(/)?>`).FindAllSubmatchIndex
-
-func (ti *TermInfo) pad(n int, mandatory bool) {
-	// NOTE this seems to be right, but also seems to produce very
-	// different results from what `tput` does.
-	var padSeq []byte
-	var numPad int
-	ospeed := -1
-	if !mandatory {
-		if ti.Booleans[XonXoff] {
-			return
-		}
-		var minBaudRate int
-		if len(ti.BigNumbers) > 0 {
-			minBaudRate = int(ti.BigNumbers[PaddingBaudRate])
-		} else {
-			minBaudRate = int(ti.Numbers[PaddingBaudRate])
-		}
-		if minBaudRate < 0 {
-			return
-		}
-		if tio, err := termios.GetAttr(ti.tty.Fd()); err == nil {
-			_, ospeed = tio.GetSpeed()
-		}
-		if ospeed < minBaudRate {
-			return
-		}
-	}
+// program is a cached Program, and what it was compiled from.
+type program struct {
+	src []byte
+	*Program
+}
 
-	if ti.Booleans[NoPadChar] || ti.Booleans[XonXoff] {
-		goto sleep
-	}
-	if ospeed < 0 {
-		if tio, err := termios.GetAttr(ti.tty.Fd()); err == nil {
-			_, ospeed = tio.GetSpeed()
-		}
-		if ospeed < 0 {
-			goto sleep
-		}
+// Unescape evaluates the string capability idx with the given args.
+// Static variables (%PA..%PZ) set by one call are visible to the
+// following ones, as in ncurses.
+func (ti *TermInfo) Unescape(idx StringIndex, args ...interface{}) ([]byte, error) {
+	p, err := ti.Program(idx)
+	if err != nil {
+		return nil, err
 	}
-	padSeq = []byte(ti.Strings[PadChar])
-	if len(padSeq) == 0 {
-		padSeq = []byte{0}
+	if ti.TParm {
+		return p.TParm(args...)
 	}
+	return p.Execute(args...)
+}
 
-	numPad = n * ospeed / 9000 / len(padSeq)
-	for i := 0; i < numPad; i++ {
-		ti.tty.Write(padSeq)
-	}
+// Program returns the string capability idx compiled, with the
+// TermInfo's static variables. Programs are cached, so this only
+// compiles idx the first time (or after it's changed in Strings).
+func (ti *TermInfo) Program(idx StringIndex) (*Program, error) {
+	ti.programsMu.Lock()
+	defer ti.programsMu.Unlock()
 
-	return
-sleep:
-	time.Sleep(time.Duration(n) * time.Millisecond)
+	src := ti.Strings[idx]
+	if p, ok := ti.programs[idx]; ok && bytes.Equal(p.src, src) {
+		return p.Program, nil
+	}
+	p, err := Compile(src)
+	if err != nil {
+		return nil, err
+	}
+	p.statics = &ti.statics
+	if ti.programs == nil {
+		ti.programs = make(map[StringIndex]*program)
+	}
+	ti.programs[idx] = &program{append([]byte(nil), src...), p}
+	return p, nil
 }
 
+// MustUnescape is like Unescape, but panics if there's an error.
 func (ti *TermInfo) MustUnescape(idx StringIndex, args ...interface{}) string {
-	buf, err := Unescape(ti.Strings[idx], args...)
+	buf, err := ti.Unescape(idx, args...)
 	if err != nil {
 		panic(err)
 	}
 	return string(buf)
 }
 
-var (
-	Black     = color.RGBA{0, 0, 0, 255}
-	Red       = color.RGBA{205, 0, 0, 255}
-	Green     = color.RGBA{0, 205, 0, 255}
-	Orange    = color.RGBA{205, 205, 0, 255}
-	Blue      = color.RGBA{0, 0, 238, 255}
-	Magenta   = color.RGBA{205, 0, 205, 255}
-	Cyan      = color.RGBA{0, 205, 205, 255}
-	LightGrey = color.RGBA{229, 229, 229, 255}
-
-	DarkGrey     = color.RGBA{127, 127, 127, 255}
-	LightRed     = color.RGBA{255, 0, 0, 255}
-	LightGreen   = color.RGBA{0, 255, 0, 255}
-	Yellow       = color.RGBA{255, 255, 0, 255}
-	LightBlue    = color.RGBA{92, 92, 255, 255}
-	LightMagenta = color.RGBA{255, 0, 255, 255}
-	LightCyan    = color.RGBA{0, 255, 255, 255}
-	White        = color.RGBA{255, 255, 255, 255}
-)
+// flag returns the boolean capability idx, or false if there's no such
+// capability in the TermInfo.
+func (ti *TermInfo) flag(idx BooleanIndex) bool {
+	return int(idx) < len(ti.Booleans) && ti.Booleans[idx]
+}
+
+// number returns the number capability idx, from whichever of Numbers
+// and BigNumbers is in use, or -1 if it's absent.
+func (ti *TermInfo) number(idx NumberIndex) int {
+	switch {
+	case int(idx) < len(ti.BigNumbers):
+		return int(ti.BigNumbers[idx])
+	case len(ti.BigNumbers) == 0 && int(idx) < len(ti.Numbers):
+		return int(ti.Numbers[idx])
+	}
+	return -1
+}
 
-var xterm = color.Palette{
-	// dark colors:
-	Black,
-	Red,
-	Green,
-	Orange,
-	Blue,
-	Magenta,
-	Cyan,
-	LightGrey,
-	// light colors:
-	DarkGrey,
-	LightRed,
-	LightGreen,
-	Yellow,
-	LightBlue,
-	LightMagenta,
-	LightCyan,
-	White,
+// cstring returns the null-terminated string found at offset idx of
+// table, or nil if idx is negative (which is how absent and cancelled
+// capabilities are stored).
+func cstring(table []byte, idx int16) ([]byte, error) {
+	if idx < 0 {
+		return nil, nil
+	}
+	if int(idx) >= len(table) {
+		return nil, fmt.Errorf("offset %d out of range (table is %d bytes)", idx, len(table))
+	}
+	for j, b := range table[idx:] {
+		if b == 0 {
+			return table[idx : int(idx)+j : int(idx)+j], nil
+		}
+	}
+	return nil, fmt.Errorf("missing null at end of string at offset %d", idx)
 }
 
-func (ti *TermInfo) Color(fg, bg color.Color) string {
-	if int(MaxColors) >= len(ti.Numbers) && int(MaxColors) > len(ti.BigNumbers) {
-		return ""
+func load1(path string, term string) (*TermInfo, error) {
+	if db := hashedName(path); isRegular(db) {
+		return loadHashed(db, term)
 	}
-	var cols int32
-	if len(ti.BigNumbers) > 0 {
-		cols = ti.BigNumbers[MaxColors]
-	} else {
-		cols = int32(ti.Numbers[MaxColors])
-	}
-	if cols <= 0 {
-		return ""
-	}
-	if cols < 88 {
-		fgi := xterm.Index(fg)
-		bgi := xterm.Index(bg)
-		return ti.MustUnescape(SetAForeground, fgi) + ti.MustUnescape(SetABackground, bgi)
-	}
-	// assume it supports ISO-8613-3
-	fR, fG, fB, fA := fg.RGBA()
-	bR, bG, bB, bA := bg.RGBA()
-	fQ := fA / 255
-	bQ := bA / 255
-	return fmt.Sprintf("\033[38;2;%d;%d;%d;48;2;%d;%d;%dm",
-		fR/fQ, fG/fQ, fB/fQ,
-		bR/bQ, bG/bQ, bB/bQ)
+
+	return loadFile(filepath.Join(path, term[:1], term))
 }
 
-func (ti *TermInfo) Puts(idx StringIndex, affcnt int, args ...interface{}) error {
-	buf, err := Unescape(ti.Strings[idx], args...)
+func loadFile(filename string) (*TermInfo, error) {
+	tif, err := os.Open(filename)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	padIndexes := findPadIndexes(buf, -1)
-	o := 0
-	for _, idx := range padIndexes {
-		_, err := ti.tty.Write(buf[o:idx[0]])
-		if err != nil {
-			return err
-		}
-		n, err := strconv.Atoi(string(buf[idx[2]:idx[3]]))
-		if err != nil {
-			return err
-		}
-		if idx[4] != -1 {
-			n *= affcnt
+	defer tif.Close()
+
+	return parse(tif, filename)
+}
+
+// findAlias looks through the directory tree at path for an entry that
+// has term amongst its aliases, for when there's no file (nor link) for
+// it. That means reading every entry, so it's only done once all else
+// has failed. Hashed databases don't need this, as every alias is a key.
+func findAlias(path string, term string) (*TermInfo, error) {
+	files, err := filepath.Glob(filepath.Join(path, "*", "*"))
+	if err != nil {
+		return nil, err
+	}
+	for _, filename := range files {
+		if !hasName(filename, term) {
+			continue
 		}
-		ti.pad(n, idx[6] != -1)
-		o = idx[1]
+		return loadFile(filename)
 	}
-	_, err = ti.tty.Write(buf[o:])
-	return err
+	return nil, &os.PathError{Op: "lookup", Path: filepath.Join(path, term), Err: os.ErrNotExist}
 }
 
-func load1(path string) (*TermInfo, error) {
-	term := os.Getenv("TERM")
-	if term == "" {
-		return nil, ErrNoTerm
+// hasName checks whether term is one of the names of the compiled entry
+// in filename, without reading the rest of the entry. As in ncurses, the
+// last name is a description and not an alias, unless it's the only one.
+func hasName(filename string, term string) bool {
+	f, err := os.Open(filename)
+	if err != nil {
+		return false
 	}
+	defer f.Close()
 
-	filename := filepath.Join(path, term[:1], term)
+	var header [6]int16
+	if err := binary.Read(f, binary.LittleEndian, header[:]); err != nil {
+		return false
+	}
+	if (header[0] != 0432 && header[0] != 01036) || header[1] <= 0 {
+		return false
+	}
+	rawNames := make([]byte, header[1])
+	if _, err := io.ReadFull(f, rawNames); err != nil {
+		return false
+	}
+	names := strings.Split(string(bytes.TrimRight(rawNames, "\x00")), "|")
+	if len(names) > 1 {
+		names = names[:len(names)-1]
+	}
+	for _, name := range names {
+		if name == term {
+			return true
+		}
+	}
+	return false
+}
 
-	tif, err := os.Open(filename)
+// Parse reads a compiled terminfo entry, as described in term(5),
+// from r. The returned TermInfo is not tied to any terminal; Puts and
+// the like output to stdout.
+func Parse(r io.Reader) (*TermInfo, error) {
+	ti, err := parse(r, "")
 	if err != nil {
 		return nil, err
 	}
+	ti.tty = os.Stdout
+	return ti, nil
+}
 
+// Unmarshal parses the compiled terminfo entry in buf. See Parse.
+func Unmarshal(buf []byte) (*TermInfo, error) {
+	return Parse(bytes.NewReader(buf))
+}
+
+// skip discards n bytes from r.
+func skip(r io.Reader, n int64) error {
+	_, err := io.CopyN(io.Discard, r, n)
+	return err
+}
+
+// parse reads a compiled terminfo entry. filename is only used for
+// error reporting.
+func parse(tif io.Reader, filename string) (*TermInfo, error) {
 	// from term(5):
 	// The header section begins the file.   This  section  contains
 	// six  short  integers  in  the  format described below.  These
@@ -445,6 +329,12 @@ func load1(path string) (*TermInfo, error) {
 		err := fmt.Errorf("expected 0432 or 01036, got %#o", header[0])
 		return nil, &ErrBadThing{Thing: "magic", Filename: filename, Err: err}
 	}
+	for i, n := range header[1:] {
+		if n < 0 || (i == 0 && n == 0) {
+			err := fmt.Errorf("bad size %d in field %d", n, i+2)
+			return nil, &ErrBadThing{Thing: "header", Filename: filename, Err: err}
+		}
+	}
 
 	rawNames := make([]byte, header[1])
 	if _, err := io.ReadFull(tif, rawNames); err != nil {
@@ -480,7 +370,9 @@ func load1(path string) (*TermInfo, error) {
 	// designed in to avoid IOT traps induced by addressing a word
 	// on an odd byte boundary).  All short integers are aligned on
 	// a short word boundary.”
-	tif.Seek(int64((header[1]+header[2])&1), 1)
+	if err := skip(tif, (int64(header[1])+int64(header[2]))&1); err != nil {
+		return nil, &ErrBadThing{Thing: "booleans section", Filename: filename, Err: err}
+	}
 
 	if isBig {
 		ti.BigNumbers = make([]int32, header[3])
@@ -506,20 +398,140 @@ func load1(path string) (*TermInfo, error) {
 
 	ti.Strings = make(map[StringIndex][]byte)
 	for i, idx := range strIndexes {
-		if idx == -1 {
-			continue
+		str, err := cstring(strTable, idx)
+		if err != nil {
+			return nil, &ErrBadThing{Thing: "strings section", Filename: filename, Err: err}
+		}
+		if str != nil {
+			ti.Strings[StringIndex(i)] = str
+		}
+	}
+
+	// Extended capabilities, if any, follow the string table,
+	// starting on an even byte.  Entries compiled without them
+	// simply end here.
+	if header[5]&1 != 0 {
+		var pad [1]byte
+		if _, err := io.ReadFull(tif, pad[:]); err == io.EOF {
+			return ti, nil
+		} else if err != nil {
+			return nil, &ErrBadThing{Thing: "extended header", Filename: filename, Err: err}
+		}
+	}
+
+	// The extended header contains five short integers:
+	//
+	//      (1) count of extended boolean capabilities
+	//
+	//      (2) count of extended numeric capabilities
+	//
+	//      (3) count of extended string capabilities
+	//
+	//      (4) count of the items in extended string table
+	//
+	//      (5) size of the extended string table in bytes
+	var extHeader [5]int16
+	if err := binary.Read(tif, binary.LittleEndian, extHeader[:]); err == io.EOF {
+		return ti, nil
+	} else if err != nil {
+		return nil, &ErrBadThing{Thing: "extended header", Filename: filename, Err: err}
+	}
+	for _, n := range extHeader {
+		if n < 0 {
+			err := fmt.Errorf("negative count %d", n)
+			return nil, &ErrBadThing{Thing: "extended header", Filename: filename, Err: err}
+		}
+	}
+
+	extBools := make([]byte, extHeader[0])
+	if _, err := io.ReadFull(tif, extBools); err != nil {
+		return nil, &ErrBadThing{Thing: "extended booleans section", Filename: filename, Err: err}
+	}
+	// same alignment dance as for the standard booleans
+	if err := skip(tif, int64(extHeader[0]&1)); err != nil {
+		return nil, &ErrBadThing{Thing: "extended booleans section", Filename: filename, Err: err}
+	}
+
+	extNums := make([]int32, extHeader[1])
+	if isBig {
+		if err := binary.Read(tif, binary.LittleEndian, extNums); err != nil {
+			return nil, &ErrBadThing{Thing: "extended numbers section", Filename: filename, Err: err}
 		}
-		var w int16
-		for j, b := range strTable[idx:] {
-			if b == 0 {
-				w = int16(j)
-				break
+	} else {
+		nums := make([]int16, extHeader[1])
+		if err := binary.Read(tif, binary.LittleEndian, nums); err != nil {
+			return nil, &ErrBadThing{Thing: "extended numbers section", Filename: filename, Err: err}
+		}
+		for i, n := range nums {
+			extNums[i] = int32(n)
+		}
+	}
+
+	// the offsets of the string values are followed by the
+	// offsets of the names of all the extended capabilities,
+	// booleans first, then numbers, then strings.
+	numNames := int(extHeader[0]) + int(extHeader[1]) + int(extHeader[2])
+	extIndexes := make([]int16, int(extHeader[2])+numNames)
+	if err := binary.Read(tif, binary.LittleEndian, extIndexes); err != nil {
+		return nil, &ErrBadThing{Thing: "extended strings section", Filename: filename, Err: err}
+	}
+
+	extTable := make([]byte, extHeader[4])
+	if _, err := io.ReadFull(tif, extTable); err != nil {
+		return nil, &ErrBadThing{Thing: "extended strings table", Filename: filename, Err: err}
+	}
+
+	// the names are stored after the last of the string values,
+	// and their offsets are relative to that.
+	extStrs := make([][]byte, extHeader[2])
+	base := 0
+	for i, idx := range extIndexes[:extHeader[2]] {
+		str, err := cstring(extTable, idx)
+		if err != nil {
+			return nil, &ErrBadThing{Thing: "extended strings section", Filename: filename, Err: err}
+		}
+		if str != nil {
+			extStrs[i] = str
+			base += len(str) + 1
+		}
+	}
+	if base > len(extTable) {
+		err := fmt.Errorf("string values overrun the table (%d > %d)", base, len(extTable))
+		return nil, &ErrBadThing{Thing: "extended strings table", Filename: filename, Err: err}
+	}
+
+	names := make([]string, numNames)
+	for i, idx := range extIndexes[extHeader[2]:] {
+		name, err := cstring(extTable[base:], idx)
+		if err != nil || len(name) == 0 {
+			if err == nil {
+				err = errors.New("missing name")
 			}
+			return nil, &ErrBadThing{Thing: "extended names", Filename: filename, Err: err}
 		}
-		ti.Strings[StringIndex(i)] = strTable[idx : idx+w]
+		names[i] = string(name)
+	}
+
+	ti.ExtBooleans = make(map[string]bool, len(extBools))
+	for i, b := range extBools {
+		ti.ExtBooleans[names[i]] = b == 1
 	}
+	names = names[len(extBools):]
 
-	// TODO: ncurses extended caps?
+	ti.ExtNumbers = make(map[string]int32, len(extNums))
+	for i, n := range extNums {
+		if n >= 0 {
+			ti.ExtNumbers[names[i]] = n
+		}
+	}
+	names = names[len(extNums):]
+
+	ti.ExtStrings = make(map[string][]byte, len(extStrs))
+	for i, str := range extStrs {
+		if str != nil {
+			ti.ExtStrings[names[i]] = str
+		}
+	}
 
 	return ti, nil
 }
@@ -591,22 +603,67 @@ func searchPath() []string {
 	return path
 }
 
-func LoadF(tty *os.File) (ti *TermInfo, err error) {
-	for _, p := range searchPath() {
-		if ti, err = load1(p); err == nil {
+// LoadTermF loads the description of the named terminal, which can be
+// any of the names it's known by, and sets it up to output to tty.
+func LoadTermF(term string, tty *os.File) (ti *TermInfo, err error) {
+	if term == "" {
+		return nil, ErrNoTerm
+	}
+	if strings.ContainsRune(term, filepath.Separator) {
+		return nil, &os.PathError{Op: "lookup", Path: term, Err: os.ErrInvalid}
+	}
+
+	path := searchPath()
+	for _, p := range path {
+		if ti, err = load1(p, term); err == nil {
+			ti.tty = tty
+			return ti, nil
+		}
+	}
+
+	for _, f := range fallbacks {
+		if buf, ok := f(term); ok {
+			if ti, err = Unmarshal(buf); err == nil {
+				ti.tty = tty
+			}
+			return ti, err
+		}
+	}
+
+	// no luck with the names the entries are filed under; as a last
+	// resort, look for aliases tic didn't make links for.
+	for _, p := range path {
+		if ti, aerr := findAlias(p, term); aerr == nil {
 			ti.tty = tty
-			break
+			return ti, nil
 		}
 	}
 
 	return ti, err
 }
 
+// LoadTerm loads the description of the named terminal, set up to
+// output to /dev/tty (or stdout if that can't be opened).
+func LoadTerm(term string) (ti *TermInfo, err error) {
+	return LoadTermF(term, openTTY())
+}
+
+// LoadF loads the description of the terminal named in $TERM, and sets
+// it up to output to tty.
+func LoadF(tty *os.File) (ti *TermInfo, err error) {
+	return LoadTermF(os.Getenv("TERM"), tty)
+}
+
+// Load loads the description of the terminal named in $TERM, set up
+// to output to /dev/tty (or stdout if that can't be opened).
 func Load() (ti *TermInfo, err error) {
+	return LoadF(openTTY())
+}
+
+func openTTY() *os.File {
 	tty, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0)
 	if err != nil {
-		tty = os.Stdout
+		return os.Stdout
 	}
-
-	return LoadF(tty)
+	return tty
 }
@@ -1,7 +1,10 @@
 package terminfo_test
 
 import (
+	"bytes"
 	"fmt"
+	"io/ioutil"
+	"os"
 	"testing"
 
 	"gopkg.in/check.v1"
@@ -190,3 +193,208 @@ func (*tiSuite) TestInitColor(c *check.C) {
 	c.Check(string(buf), check.Equals, "\x1b]4;1;rgb:FF/19/14\x1b\\")
 
 }
+
+func (*tiSuite) TestDynamicVariables(c *check.C) {
+	buf, err := terminfo.UnescapeString("%p1%Pa%p2%Pb%gb%d %ga%d %gc%d", 1, 2)
+	c.Assert(err, check.IsNil)
+	c.Check(string(buf), check.Equals, "2 1 0")
+
+	// a variable can be set more than once; the last one wins
+	buf, err = terminfo.UnescapeString("%{1}%Pz%{2}%Pz%gz%d")
+	c.Assert(err, check.IsNil)
+	c.Check(string(buf), check.Equals, "2")
+
+	// strings are fine too
+	buf, err = terminfo.UnescapeString("%p1%Px%gx%s", "hello")
+	c.Assert(err, check.IsNil)
+	c.Check(string(buf), check.Equals, "hello")
+
+	// and upper and lower case variables are distinct
+	buf, err = terminfo.UnescapeString("%{1}%Pa%{2}%PA%ga%d%gA%d")
+	c.Assert(err, check.IsNil)
+	c.Check(string(buf), check.Equals, "12")
+}
+
+func (*tiSuite) TestBadVariables(c *check.C) {
+	_, err := terminfo.UnescapeString("%{1}%P")
+	c.Check(err, check.Equals, terminfo.ErrTruncatedParametrizedString)
+	_, err = terminfo.UnescapeString("%{1}%P1")
+	c.Check(err, check.Equals, terminfo.ErrBadParametrizedString)
+	_, err = terminfo.UnescapeString("%g%")
+	c.Check(err, check.Equals, terminfo.ErrBadParametrizedString)
+}
+
+func (*tiSuite) TestStaticVariables(c *check.C) {
+	ti := &terminfo.TermInfo{Strings: map[terminfo.StringIndex][]byte{
+		terminfo.User0: []byte("%p1%PA%p1%Pa"),
+		terminfo.User1: []byte("%gA%d %ga%d"),
+	}}
+	buf, err := ti.Unescape(terminfo.User1)
+	c.Assert(err, check.IsNil)
+	c.Check(string(buf), check.Equals, "0 0")
+
+	_, err = ti.Unescape(terminfo.User0, 42)
+	c.Assert(err, check.IsNil)
+
+	// statics persist across calls, dynamics do not
+	buf, err = ti.Unescape(terminfo.User1)
+	c.Assert(err, check.IsNil)
+	c.Check(string(buf), check.Equals, "42 0")
+
+	// but not across TermInfos, nor into the package-level Unescape
+	buf, err = terminfo.Unescape(ti.Strings[terminfo.User1])
+	c.Assert(err, check.IsNil)
+	c.Check(string(buf), check.Equals, "0 0")
+}
+
+// loadTestdata loads the named entry from testdata/, restoring the
+// environment afterwards.
+func loadTestdata(c *check.C, term string) *terminfo.TermInfo {
+	ti, err := loadFrom("testdata", term)
+	c.Assert(err, check.IsNil)
+	return ti
+}
+
+// loadFrom loads the named entry with TERMINFO set to dir.
+func loadFrom(dir, term string) (*terminfo.TermInfo, error) {
+	defer setenv("TERM", term)()
+	defer setenv("TERMINFO", dir)()
+	return terminfo.LoadF(os.Stdout)
+}
+
+func (*tiSuite) TestExtended(c *check.C) {
+	for _, term := range []string{"ext-test", "ext-direct"} {
+		comment := check.Commentf(term)
+		ti := loadTestdata(c, term)
+		c.Check(ti.Names[0], check.Equals, term, comment)
+		c.Check(ti.ExtBooleans, check.DeepEquals, map[string]bool{"RGB": true, "Tc": true, "XT": true}, comment)
+		c.Check(ti.ExtNumbers, check.DeepEquals, map[string]int32{"U8": 1}, comment)
+		c.Check(ti.ExtStrings, check.DeepEquals, map[string][]byte{
+			"Ms":    []byte("\x1b]52;%p1%s;%p2%s\a"),
+			"Se":    []byte("\x1b[2 q"),
+			"Smulx": []byte("\x1b[4:%p1%dm"),
+			"Ss":    []byte("\x1b[%p1%d q"),
+			"kUP5":  []byte("\x1b[1;5A"),
+		}, comment)
+		// and the standard ones are still there
+		c.Check(string(ti.Strings[terminfo.CursorAddress]), check.Equals, "\x1b[%i%p1%d;%p2%dH", comment)
+		c.Check(ti.Booleans[terminfo.BackColorErase], check.Equals, true, comment)
+	}
+
+	ti := loadTestdata(c, "ext-test")
+	c.Check(ti.BigNumbers, check.HasLen, 0)
+	c.Check(ti.Numbers[terminfo.MaxPairs], check.Equals, int16(64))
+
+	ti = loadTestdata(c, "ext-direct")
+	c.Check(ti.Numbers, check.HasLen, 0)
+	c.Check(ti.BigNumbers[terminfo.MaxColors], check.Equals, int32(0x1000000))
+	c.Check(ti.BigNumbers[terminfo.MaxPairs], check.Equals, int32(0x10000))
+}
+
+func (*tiSuite) TestLoadTerm(c *check.C) {
+	defer os.Setenv("TERMINFO", os.Getenv("TERMINFO"))
+	os.Setenv("TERMINFO", "testdata")
+
+	// testdata has no link for ext (see terminfo.src), so that needs
+	// looking for in the names sections.
+	for _, term := range []string{"ext-test", "ext"} {
+		ti, err := terminfo.LoadTermF(term, os.Stdout)
+		c.Assert(err, check.IsNil, check.Commentf(term))
+		c.Check(ti.Names[0], check.Equals, "ext-test", check.Commentf(term))
+	}
+
+	// but the description isn't a name
+	for _, term := range []string{"a terminal with some extended capabilities", "no-such-terminal"} {
+		_, err := terminfo.LoadTermF(term, os.Stdout)
+		c.Check(os.IsNotExist(err), check.Equals, true, check.Commentf(term))
+	}
+
+	_, err := terminfo.LoadTermF("", os.Stdout)
+	c.Check(err, check.Equals, terminfo.ErrNoTerm)
+
+	_, err = terminfo.LoadTermF("../e/ext-test", os.Stdout)
+	c.Check(err, check.NotNil)
+}
+
+func (*tiSuite) TestParse(c *check.C) {
+	for _, term := range []string{"ext-test", "ext-direct"} {
+		buf, err := ioutil.ReadFile("testdata/e/" + term)
+		c.Assert(err, check.IsNil)
+
+		ti, err := terminfo.Unmarshal(buf)
+		c.Assert(err, check.IsNil)
+		c.Check(ti, check.DeepEquals, loadTestdata(c, term))
+
+		ti, err = terminfo.Parse(bytes.NewReader(buf))
+		c.Assert(err, check.IsNil)
+		c.Check(ti, check.DeepEquals, loadTestdata(c, term))
+	}
+}
+
+func (*tiSuite) TestParseTruncated(c *check.C) {
+	buf, err := ioutil.ReadFile("testdata/e/ext-test")
+	c.Assert(err, check.IsNil)
+
+	// the extended section starts right after the string table,
+	// and entries without one are fine.
+	noExt := bytes.Index(buf, []byte("\x1b[48;5;%p1%dm\x00")) + len("\x1b[48;5;%p1%dm\x00")
+	for i := 0; i < len(buf); i++ {
+		ti, err := terminfo.Unmarshal(buf[:i])
+		if i == noExt || i == noExt+1 {
+			c.Check(err, check.IsNil, check.Commentf("%d bytes", i))
+			c.Check(ti.ExtStrings, check.HasLen, 0)
+			continue
+		}
+		c.Assert(err, check.FitsTypeOf, &terminfo.ErrBadThing{}, check.Commentf("%d bytes", i))
+		c.Check(err, check.ErrorMatches, "bad .* in terminfo entry: .*", check.Commentf("%d bytes", i))
+	}
+}
+
+func (*tiSuite) TestCapNames(c *check.C) {
+	c.Check(terminfo.CursorAddress.CapName(), check.Equals, "cup")
+	c.Check(terminfo.CursorAddress.TermcapName(), check.Equals, "cm")
+	c.Check(terminfo.EnterCaMode.CapName(), check.Equals, "smcup")
+	c.Check(terminfo.EnterCaMode.TermcapName(), check.Equals, "ti")
+	c.Check(terminfo.SetAForeground.CapName(), check.Equals, "setaf")
+	c.Check(terminfo.Columns.CapName(), check.Equals, "cols")
+	c.Check(terminfo.Columns.TermcapName(), check.Equals, "co")
+	c.Check(terminfo.AutoRightMargin.CapName(), check.Equals, "am")
+	c.Check(terminfo.EnterItalicsMode.TermcapName(), check.Equals, "ZH")
+	// out of range
+	c.Check(terminfo.StringIndex(-1).CapName(), check.Equals, "")
+	c.Check((terminfo.MaxNumberIndex + 1).TermcapName(), check.Equals, "")
+
+	s, ok := terminfo.LookupString("cup")
+	c.Check(ok, check.Equals, true)
+	c.Check(s, check.Equals, terminfo.CursorAddress)
+	s, ok = terminfo.LookupTermcapString("ti")
+	c.Check(ok, check.Equals, true)
+	c.Check(s, check.Equals, terminfo.EnterCaMode)
+	// shared by smgl and smglr
+	s, ok = terminfo.LookupTermcapString("ML")
+	c.Check(ok, check.Equals, true)
+	c.Check(s, check.Equals, terminfo.SetLeftMargin)
+	n, ok := terminfo.LookupNumber("colors")
+	c.Check(ok, check.Equals, true)
+	c.Check(n, check.Equals, terminfo.MaxColors)
+	n, ok = terminfo.LookupTermcapNumber("li")
+	c.Check(ok, check.Equals, true)
+	c.Check(n, check.Equals, terminfo.Lines)
+	b, ok := terminfo.LookupBoolean("xenl")
+	c.Check(ok, check.Equals, true)
+	c.Check(b, check.Equals, terminfo.EatNewlineGlitch)
+	b, ok = terminfo.LookupTermcapBoolean("xn")
+	c.Check(ok, check.Equals, true)
+	c.Check(b, check.Equals, terminfo.EatNewlineGlitch)
+
+	_, ok = terminfo.LookupString("nope")
+	c.Check(ok, check.Equals, false)
+	_, ok = terminfo.LookupString("cm")
+	c.Check(ok, check.Equals, false)
+
+	for i := terminfo.StringIndex(0); i <= terminfo.MaxStringIndex; i++ {
+		j, ok := terminfo.LookupString(i.CapName())
+		c.Check(ok, check.Equals, true, check.Commentf("%v", i))
+		c.Check(j, check.Equals, i)
+	}
+}
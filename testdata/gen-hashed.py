@@ -0,0 +1,95 @@
+#!/usr/bin/python3
+# Generates terminfo.db and terminfo-be.db, hashed databases holding the
+# entries in e/ as tic --with-hashed-db would store them, little and big
+# endian, with small pages so the entries themselves don't fit in one:
+#
+#	./gen-hashed.py
+#
+# They're written by Berkeley DB 1.85's own hash code, by way of the copy
+# NSS keeps in its legacy dbm library. That doesn't export dbopen, so it
+# is found at its offset from legacy_Open, which is only right for the
+# library this was written against (Debian 12's libnss3 2:3.87.1-1+deb12u1,
+# amd64); other builds need the offsets below changed.
+
+import ctypes
+import os
+import struct
+
+LIBNSSDBM = "/usr/lib/x86_64-linux-gnu/libnssdbm3.so"
+LEGACY_OPEN = 0xCE70
+DBOPEN = 0x1D200
+
+# name, byte order (as a number in the order its bytes are stored), page
+# size
+DBS = [
+    ("terminfo.db", 1234, 256),
+    ("terminfo-be.db", 4321, 512),
+]
+ENTRIES = ["e/ext-test", "e/ext-direct"]
+
+DB_HASH = 1
+
+
+class DBT(ctypes.Structure):
+    _fields_ = [("data", ctypes.c_void_p), ("size", ctypes.c_size_t)]
+
+
+class HASHINFO(ctypes.Structure):
+    _fields_ = [
+        ("bsize", ctypes.c_uint),
+        ("ffactor", ctypes.c_uint),
+        ("nelem", ctypes.c_uint),
+        ("cachesize", ctypes.c_uint),
+        ("hash", ctypes.c_void_p),
+        ("lorder", ctypes.c_int),
+    ]
+
+
+lib = ctypes.CDLL(LIBNSSDBM)
+base = ctypes.cast(lib.legacy_Open, ctypes.c_void_p).value - LEGACY_OPEN
+dbopen = ctypes.CFUNCTYPE(
+    ctypes.c_void_p, ctypes.c_char_p, ctypes.c_int, ctypes.c_int, ctypes.c_int, ctypes.c_void_p
+)(base + DBOPEN)
+
+# the DB struct starts with its type, then its methods: close, del,
+# get, put, ...
+CLOSE = ctypes.CFUNCTYPE(ctypes.c_int, ctypes.c_void_p)
+PUT = ctypes.CFUNCTYPE(
+    ctypes.c_int, ctypes.c_void_p, ctypes.POINTER(DBT), ctypes.POINTER(DBT), ctypes.c_uint
+)
+
+
+def write(filename, lorder, bsize):
+    if os.path.exists(filename):
+        os.unlink(filename)
+    info = HASHINFO(bsize=bsize, lorder=lorder)
+    db = dbopen(filename.encode(), os.O_CREAT | os.O_RDWR, 0o644, DB_HASH, ctypes.addressof(info))
+    if not db:
+        raise OSError(ctypes.get_errno(), "dbopen failed", filename)
+    methods = ctypes.cast(db, ctypes.POINTER(ctypes.c_void_p))
+    close, put = CLOSE(methods[1]), PUT(methods[4])
+
+    def store(key, value):
+        k = ctypes.create_string_buffer(key, len(key))
+        v = ctypes.create_string_buffer(value, len(value))
+        if put(db, DBT(ctypes.addressof(k), len(key)), DBT(ctypes.addressof(v), len(value)), 0) != 0:
+            raise OSError("put %r failed" % key)
+
+    # as tic does: the entry under its names, with a 0 in front, and a
+    # 2 and the names under each alias but the description
+    for fn in ENTRIES:
+        with open(fn, "rb") as f:
+            data = f.read()
+        (size,) = struct.unpack_from("<h", data, 2)
+        names = data[12 : 12 + size - 1]
+        store(names, b"\0" + data)
+        aliases = names.split(b"|")
+        for alias in aliases[:-1] if len(aliases) > 1 else aliases:
+            store(alias, b"\2" + names + b"\0")
+    if close(db) != 0:
+        raise OSError("close failed", filename)
+
+
+os.chdir(os.path.dirname(os.path.abspath(__file__)))
+for filename, lorder, bsize in DBS:
+    write(filename, lorder, bsize)
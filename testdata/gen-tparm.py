@@ -0,0 +1,73 @@
+#!/usr/bin/python3
+# Generates tparm.txt, the expected output of some real entries' sgr,
+# setaf and setab for a range of parameters, as evaluated by ncurses'
+# own tparm (by way of python's curses module):
+#
+#	./gen-tparm.py > tparm.txt
+#
+# python only lets setupterm be called once, so each terminal is done
+# in a process of its own.
+
+import curses
+import os
+import subprocess
+import sys
+
+TERMS = """
+    aixterm alacritty ansi avatar cons25 contour cygwin d430-dg d470
+    dtterm Eterm foot gnome-256color hp2382 hurd iterm2 kitty konsole
+    konsole-256color linux mintty mlterm mrxvt ms-terminal nsterm pcansi
+    putty putty-256color rxvt screen screen-256color st-256color sun
+    tmux tmux-256color tvi912b tw100 vt100 vt220 vte-256color wezterm
+    wy350 wy50 xterm xterm-1002 xterm-16color xterm-256color
+    xterm-88color xterm-direct xterm-direct16 xterm-direct256
+""".split()
+
+# sgr's nine attributes: none, each on its own, and some mixes
+SGR = [[0] * 9] + [[int(i == j) for j in range(9)] for i in range(9)]
+SGR += [
+    [1, 0, 1, 0, 0, 1, 0, 0, 0],
+    [0, 1, 0, 1, 1, 0, 0, 0, 1],
+    [1, 1, 1, 1, 1, 1, 1, 1, 1],
+    [0, 1, 0, 0, 0, 1, 0, 0, 1],
+]
+COLORS = [0, 1, 7, 8, 9, 15, 16, 87, 88, 100, 255, 256, 0x123456, 0xFFFFFF]
+
+
+def quote(s):
+    out = []
+    for c in s:
+        if c in b'"\\':
+            out.append("\\" + chr(c))
+        elif 32 <= c < 127:
+            out.append(chr(c))
+        else:
+            out.append("\\x%02x" % c)
+    return '"' + "".join(out) + '"'
+
+
+def dump(term):
+    with open(os.devnull, "w") as null:
+        curses.setupterm(term, null.fileno())
+    colors = curses.tigetnum("colors")
+    for cap, paramss in [
+        ("sgr", SGR),
+        ("setaf", [[n] for n in COLORS if n < colors]),
+        ("setab", [[n] for n in COLORS if n < colors]),
+    ]:
+        tpl = curses.tigetstr(cap)
+        if not tpl:
+            continue
+        for params in paramss:
+            out = curses.tparm(tpl, *params)
+            print(term, cap, ",".join(map(str, params)), quote(tpl), quote(out), sep="\t")
+
+
+if len(sys.argv) > 1:
+    dump(sys.argv[1])
+else:
+    print("# generated by gen-tparm.py; do not edit")
+    print("# term\tcapname\tparams\ttemplate\toutput")
+    sys.stdout.flush()
+    for term in TERMS:
+        subprocess.run([sys.executable, __file__, term], check=True)